@@ -0,0 +1,222 @@
+package vortice
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"vortice/business"
+	"vortice/config"
+	"vortice/container"
+	"vortice/object"
+	"vortice/util"
+
+	"go.uber.org/zap"
+)
+
+var (
+	defaultApp  *App
+	defaultOnce = &sync.Once{}
+)
+
+// DefaultApp returns the process-wide App backing the package-level functions,
+// built on container.DefaultCore and business.DefaultCore.
+func DefaultApp() *App {
+	defaultOnce.Do(func() {
+		defaultApp = newApp(container.DefaultCore(), business.DefaultCore())
+	})
+	return defaultApp
+}
+
+// App is an independent vortice instance: a container and the business core built on it.
+// Registrations and objects of one App are invisible to any other, so several Apps can share a process
+// and tests can each use their own. Go has no generic methods, so the generic operations are the free
+// functions GetFrom, GetElemFrom and RegisterTo0..6 taking the App as their first argument.
+type App struct {
+	core *container.Core
+	biz  *business.Core
+	cfg  *config.Config
+}
+
+// NewApp creates an App with its own container and business core, and an empty configuration.
+func NewApp(ctx context.Context) *App {
+	core := container.NewCore(ctx)
+	return newApp(core, business.NewCore(core))
+}
+
+// newApp creates an App on the cores with an empty configuration, which is also the value source of the container.
+func newApp(core *container.Core, biz *business.Core) *App {
+	cfg := config.New()
+	core.AddValueSource(cfg)
+	return &App{core: core, biz: biz, cfg: cfg}
+}
+
+// Container returns the App's container core.
+func (a *App) Container() *container.Core {
+	return a.core
+}
+
+// Business returns the App's business core.
+func (a *App) Business() *business.Core {
+	return a.biz
+}
+
+// Config returns the App's configuration, to which sources are added before Init.
+func (a *App) Config() *config.Config {
+	return a.cfg
+}
+
+// SetRandomOrder makes Init create independent components in an order shuffled with the given seed, to
+// surface hidden order dependencies in tests; the seed is logged so a failing order can be reproduced.
+func (a *App) SetRandomOrder(seed int64) {
+	a.core.SetRandomOrder(seed)
+}
+
+// Init loads the configuration sources, then initializes the container and the plugins of the App.
+func (a *App) Init() error {
+	if err := a.cfg.Load(); err != nil {
+		return err
+	}
+	return a.biz.Init()
+}
+
+// Start starts the auto-startup services and then the plugins of the App.
+func (a *App) Start() error {
+	return a.biz.Start()
+}
+
+// Shutdown stops the plugins and services of the App and destroys its objects and plugins. Only the first
+// call has an effect.
+func (a *App) Shutdown() {
+	a.biz.Shutdown()
+}
+
+// GetElemFrom retrieves an object of the specified pointer type from the App's container within the given context.
+func GetElemFrom[T any](app *App, ctx context.Context, typ *T) T {
+	coreCtx := container.WithCoreContext(ctx)
+	objs, err := app.core.GetObjects(coreCtx, typ)
+	if err != nil || len(objs) == 0 {
+		return zeroVal(typ).(T)
+	}
+	return objs[0].Instance().(T)
+}
+
+// GetFrom retrieves an object of the specified type from the App's container within the given context,
+// returning the zero value of T if none is found.
+func GetFrom[T any](app *App, ctx context.Context, typ T) T {
+	coreCtx := container.WithCoreContext(ctx)
+	objs, err := app.core.GetObjects(coreCtx, typ)
+	if err != nil || len(objs) == 0 {
+		var zero T
+		return zero
+	}
+	return objs[0].Instance().(T)
+}
+
+// RegisterTo0 registers a factory function that takes no arguments with the App.
+func RegisterTo0[T any, FN object.FactoryFunc0[T]](app *App, fn FN, opts ...Option) {
+	registerTo(app, fn, opts...)
+}
+
+// RegisterTo1 registers a factory function creating T from A with the App.
+func RegisterTo1[T, A any, FN object.FactoryFunc1[T, A]](app *App, fn FN, opts ...Option) {
+	registerTo(app, fn, opts...)
+}
+
+// RegisterTo2 registers a factory function that takes two arguments with the App.
+func RegisterTo2[T, A, B any, FN object.FactoryFunc2[T, A, B]](app *App, fn FN, opts ...Option) {
+	registerTo(app, fn, opts...)
+}
+
+// RegisterTo3 registers a factory function that takes three arguments with the App.
+func RegisterTo3[T, A, B, C any, FN object.FactoryFunc3[T, A, B, C]](app *App, fn FN, opts ...Option) {
+	registerTo(app, fn, opts...)
+}
+
+// RegisterTo4 registers a factory function that takes four arguments with the App.
+func RegisterTo4[T, A, B, C, D any, FN object.FactoryFunc4[T, A, B, C, D]](app *App, fn FN, opts ...Option) {
+	registerTo(app, fn, opts...)
+}
+
+// RegisterTo5 registers a factory function that takes five arguments with the App.
+func RegisterTo5[T, A, B, C, D, E any, FN object.FactoryFunc5[T, A, B, C, D, E]](
+	app *App, fn FN, opts ...Option) {
+	registerTo(app, fn, opts...)
+}
+
+// RegisterTo6 registers a factory function that takes six arguments with the App.
+func RegisterTo6[T, A, B, C, D, E, F any, FN object.FactoryFunc6[T, A, B, C, D, E, F]](
+	app *App, fn FN, opts ...Option) {
+	registerTo(app, fn, opts...)
+}
+
+// registerTo registers a factory function with the App's container, applying given options.
+func registerTo(app *App, fn any, opts ...Option) {
+	prop := object.NewProperty()
+	for _, option := range opts {
+		option(prop)
+	}
+	prop.SetTags(container.TagAutowired)
+	if _, err := app.core.RegisterFactory(fn, prop, true); err != nil {
+		util.Logger().Panic("register", zap.Error(err))
+	}
+}
+
+// ValueTo sets a named value of the App, injected into the fields of In structs tagged with the key.
+// The App's configuration takes precedence, so values set this way act as defaults.
+func ValueTo(app *App, key string, value any) {
+	app.core.SetValue(key, value)
+}
+
+// BindConfigTo registers *T as a singleton component of the App, bound from the configuration section
+// when the App initializes. Init fails, naming the key, if the section is invalid.
+func BindConfigTo[T any](app *App, section string, opts ...Option) {
+	ptr := new(T)
+	prop := object.NewProperty()
+	for _, option := range opts {
+		option(prop)
+	}
+	prop.SetTags(container.TagAutowired)
+	prop.AddChecks(func() error {
+		return app.cfg.Bind(section, ptr)
+	})
+	def, err := object.ParseInstance(reflect.TypeOf(ptr), ptr, prop)
+	if err != nil {
+		util.Logger().Panic("BindConfig", zap.Error(err))
+	}
+	if err := app.core.RegisterDefinition(def, true); err != nil {
+		util.Logger().Panic("BindConfig", zap.Error(err))
+	}
+}
+
+// BindReloadableConfigTo registers *config.Reloadable[T] as a singleton component of the App, bound from the
+// configuration section when the App initializes and rebound by every Config.Reload. *T is registered too,
+// with the Prototype scope, so every lookup returns the latest value; components that must see changes
+// should depend on the Reloadable and register OnChange listeners instead of capturing *T. The options apply
+// to the Reloadable only; *T is selected whenever the Reloadable is.
+func BindReloadableConfigTo[T any](app *App, section string, opts ...Option) {
+	r := config.NewReloadable[T](app.cfg, section)
+	handle := object.NewProperty()
+	for _, option := range opts {
+		option(handle)
+	}
+	handle.SetTags(container.TagAutowired)
+	handle.AddChecks(r.Update)
+	value := object.NewProperty()
+	value.SetTags(container.TagAutowired)
+	value.Scope = object.Prototype
+	value.AddConditions(object.OnPresentCondition(object.GenerateDefinitionName(reflect.TypeOf(r))))
+	def, err := object.ParseInstance(reflect.TypeOf(r), r, handle)
+	if err != nil {
+		util.Logger().Panic("BindReloadableConfig", zap.Error(err))
+	}
+	valueDef, err := object.ParseSupplier(reflect.TypeOf((*T)(nil)), func() any { return r.Load() }, value)
+	if err != nil {
+		util.Logger().Panic("BindReloadableConfig", zap.Error(err))
+	}
+	for _, d := range []*object.Definition{def, valueDef} {
+		if err := app.core.RegisterDefinition(d, true); err != nil {
+			util.Logger().Panic("BindReloadableConfig", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package vortice
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"vortice/config"
+)
+
+type appSvc struct{ name string }
+type appDep struct{}
+
+func newAppDep() *appDep               { return &appDep{} }
+func newAppSvc(d *appDep) *appSvc      { return &appSvc{name: "svc"} }
+func newAppSvcOther(d *appDep) *appSvc { return &appSvc{name: "other"} }
+
+// ---------- 多个 App 互相隔离 ----------
+func TestApp_Isolated(t *testing.T) {
+	ctx := context.Background()
+	a1, a2 := NewApp(ctx), NewApp(ctx)
+	RegisterTo0(a1, newAppDep)
+	RegisterTo1(a1, newAppSvc)
+	RegisterTo0(a2, newAppDep)
+	RegisterTo1(a2, newAppSvcOther)
+	for _, app := range []*App{a1, a2} {
+		if err := app.Init(); err != nil {
+			t.Fatalf("init failed: %v", err)
+		}
+		if err := app.Start(); err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+	}
+	defer a1.Shutdown()
+	defer a2.Shutdown()
+
+	if s := GetFrom(a1, ctx, (*appSvc)(nil)); s == nil || s.name != "svc" {
+		t.Fatalf("a1 should resolve its own registration, got %+v", s)
+	}
+	if s := GetFrom(a2, ctx, (*appSvc)(nil)); s == nil || s.name != "other" {
+		t.Fatalf("a2 should resolve its own registration, got %+v", s)
+	}
+	// 未注册到默认 App 的类型不应被全局函数找到
+	if s := Get(ctx, (*appSvc)(nil)); s != nil {
+		t.Fatalf("registrations of an App must not leak into the default App")
+	}
+}
+
+func TestDefaultApp_BacksPackageFunctions(t *testing.T) {
+	if DefaultApp() != DefaultApp() {
+		t.Fatalf("DefaultApp should return the same instance")
+	}
+	if DefaultApp().Container() == nil || DefaultApp().Business().Container() != DefaultApp().Container() {
+		t.Fatalf("DefaultApp should wrap the default container and business cores")
+	}
+}
+
+type appDBConfig struct {
+	Host string `validate:"required"`
+	Port int    `default:"5432"`
+}
+
+type appRepo struct{ cfg *appDBConfig }
+
+func newAppRepo(cfg *appDBConfig) *appRepo { return &appRepo{cfg: cfg} }
+
+// 配置段绑定为组件，可被其他组件注入
+func TestBindConfigTo(t *testing.T) {
+	ctx := context.Background()
+	app := NewApp(ctx)
+	app.Config().Add(config.Map(map[string]any{"db": map[string]any{"host": "localhost"}}))
+	BindConfigTo[appDBConfig](app, "db")
+	RegisterTo1(app, newAppRepo)
+	if err := app.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer app.Shutdown()
+	repo := GetFrom(app, ctx, (*appRepo)(nil))
+	if repo == nil || repo.cfg.Host != "localhost" || repo.cfg.Port != 5432 {
+		t.Fatalf("unexpected config injected: %+v", repo)
+	}
+	if cfg := GetFrom(app, ctx, (*appDBConfig)(nil)); cfg != repo.cfg {
+		t.Fatalf("bound config should be a singleton")
+	}
+}
+
+// 缺少必填键时 Init 失败，并给出完整键名
+func TestBindConfigTo_MissingKey(t *testing.T) {
+	app := NewApp(context.Background())
+	BindConfigTo[appDBConfig](app, "db")
+	err := app.Init()
+	if err == nil || !strings.Contains(err.Error(), "config key db.host: required but not set") {
+		t.Fatalf("init should name the missing key, got %v", err)
+	}
+}
+
+func TestApp_InitFailsOnConfigLoad(t *testing.T) {
+	app := NewApp(context.Background())
+	app.Config().Add(config.File("testdata/missing.yaml"))
+	if err := app.Init(); err == nil || !strings.Contains(err.Error(), "missing.yaml") {
+		t.Fatalf("init should fail when a source cannot be loaded, got %v", err)
+	}
+}
+
+type appLimits struct {
+	Rate int `default:"10"`
+}
+
+type appLimiter struct{ rate int }
+
+func newAppLimiter(r *config.Reloadable[appLimits]) *appLimiter {
+	l := &appLimiter{rate: r.Get().Rate}
+	r.OnChange(func(_, new appLimits) { l.rate = new.Rate })
+	return l
+}
+
+// 重新加载后容器中的配置值被替换，监听者收到通知
+func TestBindReloadableConfigTo(t *testing.T) {
+	ctx := context.Background()
+	values := map[string]any{}
+	app := NewApp(ctx)
+	app.Config().Add(config.Map(values))
+	BindReloadableConfigTo[appLimits](app, "limits")
+	RegisterTo1(app, newAppLimiter)
+	if err := app.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer app.Shutdown()
+	limiter := GetFrom(app, ctx, (*appLimiter)(nil))
+	if limiter == nil || limiter.rate != 10 {
+		t.Fatalf("expected the default rate, got %+v", limiter)
+	}
+
+	app.Config().Add(config.Map(map[string]any{"limits.rate": 50}))
+	if err := app.Config().Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if limiter.rate != 50 {
+		t.Fatalf("listener should see the new rate, got %d", limiter.rate)
+	}
+	if v := GetFrom(app, ctx, (*appLimits)(nil)); v == nil || v.Rate != 50 {
+		t.Fatalf("container should return the swapped value, got %+v", v)
+	}
+}
+
+// 选项只作用于 Reloadable，*T 随 Reloadable 一起被选中
+func TestBindReloadableConfigTo_Options(t *testing.T) {
+	ctx := context.Background()
+	app := NewApp(ctx)
+	BindReloadableConfigTo[appLimits](app, "limits", WithAutoStartup())
+	if err := app.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer app.Shutdown()
+	for _, def := range app.Container().GetDefinitions() {
+		handle := strings.Contains(def.Name(), "Reloadable")
+		if handle != def.AutoStartup() {
+			t.Fatalf("options should apply to the Reloadable only, got %s with autoStartup=%v", def.Name(), def.AutoStartup())
+		}
+	}
+	if v := GetFrom(app, ctx, (*appLimits)(nil)); v == nil || v.Rate != 10 {
+		t.Fatalf("expected the default rate, got %+v", v)
+	}
+
+	excluded := NewApp(ctx)
+	excluded.Container().SetProfiles("prod")
+	BindReloadableConfigTo[appLimits](excluded, "limits", WithProfile("dev"))
+	if err := excluded.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer excluded.Shutdown()
+	if defs := excluded.Container().GetDefinitions(); len(defs) != 0 {
+		t.Fatalf("*T should be excluded with the Reloadable, got %v", defs)
+	}
+}
+
+type appServerParams struct {
+	In
+	Addr    string        `value:"http.addr"`
+	Timeout time.Duration `value:"http.timeout"`
+	Dep     *appDep
+}
+
+type appServer struct {
+	addr    string
+	timeout time.Duration
+}
+
+func newAppServer(p appServerParams) *appServer {
+	return &appServer{addr: p.Addr, timeout: p.Timeout}
+}
+
+// 配置中的值优先于 ValueTo 设置的默认值
+func TestValueTo(t *testing.T) {
+	ctx := context.Background()
+	app := NewApp(ctx)
+	app.Config().Add(config.Map(map[string]any{"http": map[string]any{"timeout": "3s"}}))
+	ValueTo(app, "http.addr", ":8080")
+	ValueTo(app, "http.timeout", time.Second)
+	RegisterTo0(app, newAppDep)
+	RegisterTo1(app, newAppServer)
+	if err := app.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer app.Shutdown()
+	srv := GetFrom(app, ctx, (*appServer)(nil))
+	if srv == nil || srv.addr != ":8080" || srv.timeout != 3*time.Second {
+		t.Fatalf("unexpected server %+v", srv)
+	}
+}
+
+func TestValueTo_MissingKey(t *testing.T) {
+	app := NewApp(context.Background())
+	RegisterTo0(app, newAppDep)
+	RegisterTo1(app, newAppServer)
+	err := app.Init()
+	if err == nil || !strings.Contains(err.Error(), "value http.addr is not set") {
+		t.Fatalf("init should name the missing value, got %v", err)
+	}
+}
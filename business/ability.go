@@ -1,6 +1,20 @@
 package business
 
-import "vortice/container"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"vortice/container"
+	"vortice/object"
+)
+
+var (
+	// ErrAbilityNotFound is the error returned when neither an ability nor an extension provides the extension for a target.
+	ErrAbilityNotFound = errors.New("ability not found")
+)
 
 type (
 	// Target is a type that can represent any value, used for generic or flexible type handling.
@@ -57,3 +71,73 @@ func (ba BaseAbility[O, E]) Priority() int {
 func (ba BaseAbility[O, E]) DefaultImpl() E {
 	return ba.ext
 }
+
+// abilityKey identifies the abilities of a target type providing an extension type.
+type abilityKey struct {
+	target reflect.Type
+	ext    reflect.Type
+}
+
+// abilityKeyOf returns the key of an ability factory of type func(O, E) T, or the zero key for other functions.
+func abilityKeyOf(fnType reflect.Type) abilityKey {
+	if fnType.Kind() != reflect.Func || fnType.NumIn() != 2 {
+		return abilityKey{}
+	}
+	return abilityKey{target: fnType.In(0), ext: fnType.In(1)}
+}
+
+// GetAbilityWith returns the extension E the abilities of the given Core provide for the target. Every ability
+// registered for O and E in the resolution chain of the namespace of ctx is created for the target, receiving
+// the namespace extension E, or the zero E if there is none. Among the abilities whose Support and Enabled
+// return true, the DefaultImpl of the one with the highest Priority is returned; equal priorities are
+// resolved in resolution chain order, then in registration order. If no ability qualifies, the namespace
+// extension is returned, or else the DefaultImpl of the ability with the highest Priority.
+func GetAbilityWith[O Target, E Extension](c *Core, ctx context.Context, target O) (E, error) {
+	var zero E
+	ext, err := GetExtension[E](c, ctx)
+	if err != nil && !errors.Is(err, ErrExtensionNotFound) {
+		return zero, err
+	}
+	hasExt := err == nil
+	key := abilityKey{target: reflect.TypeOf((*O)(nil)).Elem(), ext: reflect.TypeOf((*E)(nil)).Elem()}
+	argv := []reflect.Value{reflect.ValueOf(&target).Elem(), reflect.ValueOf(&ext).Elem()}
+	var best, fallback Ability[O, E]
+	for _, def := range c.abilityCandidates(key, c.ResolutionChainOf(ctx)) {
+		ability, ok := def.Factory().Call(argv).Interface().(Ability[O, E])
+		if !ok {
+			return zero, fmt.Errorf("ability %s does not implement the ability of %v", def.ID(), key.target)
+		}
+		if fallback == nil || ability.Priority() > fallback.Priority() {
+			fallback = ability
+		}
+		if ability.Support() && ability.Enabled() && (best == nil || ability.Priority() > best.Priority()) {
+			best = ability
+		}
+	}
+	switch {
+	case best != nil:
+		return best.DefaultImpl(), nil
+	case hasExt:
+		return ext, nil
+	case fallback != nil:
+		if impl := fallback.DefaultImpl(); !reflect.ValueOf(&impl).Elem().IsZero() {
+			return impl, nil
+		}
+	}
+	return zero, fmt.Errorf("%w: %v for target %v", ErrAbilityNotFound, key.ext, key.target)
+}
+
+// abilityCandidates returns the ability definitions of the key registered in the namespaces, in namespace order.
+func (c *Core) abilityCandidates(key abilityKey, namespaces []string) []*object.Definition {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	var candidates []*object.Definition
+	for _, ns := range namespaces {
+		for _, def := range c.abilities[key] {
+			if slices.ContainsFunc(def.Tags(), newNamespaceTag(ns).Equals) {
+				candidates = append(candidates, def)
+			}
+		}
+	}
+	return candidates
+}
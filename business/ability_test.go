@@ -0,0 +1,147 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type order struct {
+	amount int
+	vip    bool
+}
+
+type discount interface{ Rate() int }
+type fixedDiscount int
+
+func (d fixedDiscount) Rate() int { return int(d) }
+
+// bigOrderAbility 仅支持大额订单
+type bigOrderAbility struct {
+	BaseAbility[*order, discount]
+	o *order
+}
+
+func (a bigOrderAbility) Support() bool         { return a.o.amount >= 100 }
+func (a bigOrderAbility) Priority() int         { return 10 }
+func (a bigOrderAbility) DefaultImpl() discount { return fixedDiscount(20) }
+
+// vipAbility 支持 VIP 订单，优先级更高
+type vipAbility struct {
+	BaseAbility[*order, discount]
+	o       *order
+	enabled bool
+}
+
+func (a vipAbility) Support() bool         { return a.o.vip }
+func (a vipAbility) Enabled() bool         { return a.enabled }
+func (a vipAbility) Priority() int         { return 50 }
+func (a vipAbility) DefaultImpl() discount { return fixedDiscount(30) }
+
+func newBigOrderAbility(o *order, ext discount) bigOrderAbility {
+	return bigOrderAbility{o: o}
+}
+
+func newVipAbility(o *order, ext discount) vipAbility {
+	return vipAbility{o: o, enabled: true}
+}
+
+func newDisabledVipAbility(o *order, ext discount) vipAbility {
+	return vipAbility{o: o, enabled: false}
+}
+
+func newMainDiscount() discount { return fixedDiscount(5) }
+
+func TestGetAbilityWith(t *testing.T) {
+	c := newCore()
+	RegisterAbilityTo[*order, discount](c, newBigOrderAbility)
+	RegisterAbilityTo[*order, discount](c, newVipAbility)
+	RegisterExtTo0(c, newMainDiscount)
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	ctx := context.Background()
+	cases := []struct {
+		o    *order
+		want int
+	}{
+		{&order{amount: 10}, 5},              // 无能力支持，回退到命名空间扩展
+		{&order{amount: 200}, 20},            // 大额订单
+		{&order{amount: 200, vip: true}, 30}, // 两者都支持，取优先级高者
+	}
+	for _, tc := range cases {
+		d, err := GetAbilityWith[*order, discount](c, ctx, tc.o)
+		if err != nil || d.Rate() != tc.want {
+			t.Fatalf("order %+v: expected %d, got %v %v", tc.o, tc.want, d, err)
+		}
+	}
+}
+
+// Enabled 为 false 的能力不参与选择
+func TestGetAbilityWith_Disabled(t *testing.T) {
+	c := newCore()
+	RegisterAbilityTo[*order, discount](c, newDisabledVipAbility)
+	RegisterExtTo0(c, newMainDiscount)
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	d, err := GetAbilityWith[*order, discount](c, context.Background(), &order{vip: true})
+	if err != nil || d.Rate() != 5 {
+		t.Fatalf("disabled ability should fall back to the extension, got %v %v", d, err)
+	}
+}
+
+// 无扩展时回退到优先级最高能力的 DefaultImpl，均无则报错
+func TestGetAbilityWith_DefaultImplFallback(t *testing.T) {
+	c := newCore()
+	RegisterAbilityTo[*order, discount](c, newBigOrderAbility)
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	d, err := GetAbilityWith[*order, discount](c, context.Background(), &order{amount: 1})
+	if err != nil || d.Rate() != 20 {
+		t.Fatalf("expected the DefaultImpl fallback, got %v %v", d, err)
+	}
+
+	empty := newCore()
+	if err := empty.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, err := GetAbilityWith[*order, discount](empty, context.Background(), &order{}); !errors.Is(err, ErrAbilityNotFound) {
+		t.Fatalf("expected ErrAbilityNotFound, got %v", err)
+	}
+}
+
+// 插件注册的能力只对该插件命名空间生效
+func TestGetAbilityWith_PluginNamespace(t *testing.T) {
+	c := newCore()
+	RegisterExtTo0(c, newMainDiscount)
+	vip := NewPlugin("vip")
+	vip.Init(func() error {
+		RegisterAbilityTo[*order, discount](c, newVipAbility)
+		return nil
+	})
+	RegisterPluginTo(c, vip)
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	o := &order{vip: true}
+	if d, _ := GetAbilityWith[*order, discount](c, WithContext(context.Background(), "vip"), o); d == nil || d.Rate() != 30 {
+		t.Fatalf("vip namespace should use the plugin ability, got %v", d)
+	}
+	if d, _ := GetAbilityWith[*order, discount](c, context.Background(), o); d == nil || d.Rate() != 5 {
+		t.Fatalf("main namespace should not see the plugin ability, got %v", d)
+	}
+}
+
+// 能力不再注册到容器中
+func TestRegisterAbility_NotInContainer(t *testing.T) {
+	c := newCore()
+	RegisterAbilityTo[*order, discount](c, newBigOrderAbility)
+	if n := len(c.Container().GetDefinitions()); n != 0 {
+		t.Fatalf("abilities must not be container definitions, got %d", n)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatalf("init should not require the target as a component: %v", err)
+	}
+}
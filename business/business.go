@@ -1,6 +1,12 @@
+// Package business selects the extensions serving a request by its business identity: plugins register
+// extensions in namespaces, and the namespace chain of a request is resolved from its attributes. Transports
+// install the identity once per request, with HTTPMiddleware for net/http or MetadataContext from a gRPC
+// interceptor or any other transport carrying string metadata.
 package business
 
 import (
+	"context"
+
 	"vortice/object"
 	"vortice/util"
 
@@ -10,28 +16,49 @@ import (
 // Option is a function type for configuring Property with functional options.
 type Option object.Option
 
-//// GetAbility retrieves the extension for a given ability type and target object within the provided context.
-//func GetAbility[O Target, E Extension, T Ability[O, E]](ctx Context, typ T, obj O) E {
-//	return nil
-//}
-//
-//// GetAbilities retrieves the extension associated with a specific ability for a given object within a context.
-//func GetAbilities[O Target, E Extension, T Ability[O, E]](ctx Context, typ T, obj O) []E {
-//	return nil
-//}
-
-// RegisterAbility registers a factory function for creating an Ability, with optional configuration.
+// GetAbility returns the extension E the abilities of the default Core provide for the target. See GetAbilityWith.
+func GetAbility[O Target, E Extension](ctx context.Context, target O) (E, error) {
+	return GetAbilityWith[O, E](DefaultCore(), ctx, target)
+}
+
+// RegisterAbility registers a factory function for creating an Ability with the default core, with optional configuration.
 func RegisterAbility[O Target, E Extension, T Ability[O, E], FN AbilityFactory[O, E, T]](fn FN, opts ...Option) {
-	RegisterExtN(fn, opts...)
+	RegisterAbilityTo[O, E, T](DefaultCore(), fn, opts...)
+}
+
+// RegisterAbilityTo registers a factory function for creating an Ability with the given core, panicking if an error occurs.
+func RegisterAbilityTo[O Target, E Extension, T Ability[O, E], FN AbilityFactory[O, E, T]](c *Core, fn FN, opts ...Option) {
+	prop := object.NewProperty()
+	for _, option := range opts {
+		option(prop)
+	}
+	if _, err := c.RegisterAbility(fn, prop); err != nil {
+		util.Logger().Panic("RegisterAbility", zap.Error(err))
+	}
 }
 
 // RegisterPlugin registers a plugin with the default core, panicking if an error occurs.
 func RegisterPlugin(plugin *Plugin) {
-	if err := DefaultCore().RegisterPlugin(plugin); err != nil {
+	RegisterPluginTo(DefaultCore(), plugin)
+}
+
+// RegisterPluginTo registers a plugin with the given core, panicking if an error occurs.
+func RegisterPluginTo(c *Core, plugin *Plugin) {
+	if err := c.RegisterPlugin(plugin); err != nil {
 		util.Logger().Panic("RegisterPlugin", zap.Error(err))
 	}
 }
 
+// DisablePlugin disables a plugin of the default core at runtime. See Core.DisablePlugin.
+func DisablePlugin(name string) error {
+	return DefaultCore().DisablePlugin(name)
+}
+
+// EnablePlugin enables a disabled plugin of the default core at runtime. See Core.EnablePlugin.
+func EnablePlugin(name string) error {
+	return DefaultCore().EnablePlugin(name)
+}
+
 // RegisterExt0 registers a factory function that takes no arguments and returns a value of type T, with optional configuration.
 func RegisterExt0[T any, FN object.FactoryFunc0[T]](fn FN, opts ...Option) {
 	RegisterExtN(fn, opts...)
@@ -71,11 +98,53 @@ func RegisterExt6[T, A, B, C, D, E, F any, FN object.FactoryFunc6[T, A, B, C, D,
 
 // RegisterExtN adds a factory function to the system with given options and sets an extension tag.
 func RegisterExtN(fn any, opts ...Option) {
+	RegisterExtNTo(DefaultCore(), fn, opts...)
+}
+
+// RegisterExtTo0 registers a factory function that takes no arguments with the given core.
+func RegisterExtTo0[T any, FN object.FactoryFunc0[T]](c *Core, fn FN, opts ...Option) {
+	RegisterExtNTo(c, fn, opts...)
+}
+
+// RegisterExtTo1 registers a factory function creating T from A with the given core.
+func RegisterExtTo1[T, A any, FN object.FactoryFunc1[T, A]](c *Core, fn FN, opts ...Option) {
+	RegisterExtNTo(c, fn, opts...)
+}
+
+// RegisterExtTo2 registers a factory function with two arguments with the given core.
+func RegisterExtTo2[T, A, B any, FN object.FactoryFunc2[T, A, B]](c *Core, fn FN, opts ...Option) {
+	RegisterExtNTo(c, fn, opts...)
+}
+
+// RegisterExtTo3 registers a factory function with three arguments with the given core.
+func RegisterExtTo3[T, A, B, C any, FN object.FactoryFunc3[T, A, B, C]](c *Core, fn FN, opts ...Option) {
+	RegisterExtNTo(c, fn, opts...)
+}
+
+// RegisterExtTo4 registers a factory function with four arguments with the given core.
+func RegisterExtTo4[T, A, B, C, D any, FN object.FactoryFunc4[T, A, B, C, D]](c *Core, fn FN, opts ...Option) {
+	RegisterExtNTo(c, fn, opts...)
+}
+
+// RegisterExtTo5 registers a factory function with five arguments with the given core.
+func RegisterExtTo5[T, A, B, C, D, E any, FN object.FactoryFunc5[T, A, B, C, D, E]](
+	c *Core, fn FN, opts ...Option) {
+	RegisterExtNTo(c, fn, opts...)
+}
+
+// RegisterExtTo6 registers a factory function with six arguments with the given core.
+func RegisterExtTo6[T, A, B, C, D, E, F any, FN object.FactoryFunc6[T, A, B, C, D, E, F]](
+	c *Core, fn FN, opts ...Option) {
+	RegisterExtNTo(c, fn, opts...)
+}
+
+// RegisterExtNTo adds a factory function to the given core with given options and sets an extension tag.
+func RegisterExtNTo(c *Core, fn any, opts ...Option) {
 	prop := object.NewProperty()
 	for _, option := range opts {
 		option(prop)
 	}
-	if _, err := DefaultCore().RegisterExtension(fn, prop); err != nil {
+	if _, err := c.RegisterExtension(fn, prop); err != nil {
 		util.Logger().Panic("RegisterExtension", zap.Error(err))
 	}
 }
@@ -3,11 +3,16 @@ package business
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"sync"
 	"sync/atomic"
 
 	"vortice/container"
 	"vortice/object"
+	"vortice/util"
+
+	"go.uber.org/zap"
 )
 
 var (
@@ -40,19 +45,29 @@ func newNamespaceTag(ns string) object.Tag {
 	return object.NewTag("namespace", ns)
 }
 
+// extensionParser parses the definition of an extension with the property carrying its tags.
+type extensionParser func(prop *object.Property) (*object.Definition, error)
+
 // Core encapsulates the container's core, a set of plugins, and a mutex for thread-safe operations.
 type Core struct {
-	core       *container.Core
-	readonly   *atomic.Bool
-	plugins    *sync.Map
-	mutex      *sync.RWMutex
-	current    *Plugin
-	extensions map[string]*object.Definition
-	abilities  map[string][]*object.Definition
+	core        *container.Core
+	readonly    *atomic.Bool
+	plugins     *sync.Map
+	mutex       *sync.RWMutex
+	current     *Plugin
+	extensions  map[string]*object.Definition
+	abilities   map[abilityKey][]*object.Definition
+	rules       *RuleResolver
+	resolver    BizIdentityResolver
+	hostVersion string
+	lifecycle   *sync.Mutex
+	initialized []*Plugin
+	started     []*Plugin
 }
 
 // NewCore initializes a new Core instance with the provided container.Core, setting up a mutex and an empty plugin list.
 func NewCore(core *container.Core) *Core {
+	rules := NewRuleResolver()
 	return &Core{
 		core:       core,
 		readonly:   &atomic.Bool{},
@@ -60,70 +75,151 @@ func NewCore(core *container.Core) *Core {
 		current:    nil,
 		mutex:      &sync.RWMutex{},
 		extensions: map[string]*object.Definition{},
-		abilities:  map[string][]*object.Definition{},
+		abilities:  map[abilityKey][]*object.Definition{},
+		rules:      rules,
+		resolver:   rules,
+		lifecycle:  &sync.Mutex{},
 	}
 }
 
-// Init initializes the Core and its plugins, setting it to readonly and preparing for operation.
+// Container returns the container core the Core is built on.
+func (c *Core) Container() *container.Core {
+	return c.core
+}
+
+// Init initializes the plugins and then the container, setting the Core to readonly. Plugins are
+// initialized first so that their init functions can register extensions before the container is locked,
+// parents and required plugins before the plugins depending on them; Init fails without initializing any
+// plugin if ValidatePlugins reports a problem.
 func (c *Core) Init() error {
 	if ok := c.readonly.CompareAndSwap(false, true); !ok {
 		return ErrInitialized
 	}
-	var err error
-	if err = c.core.Init(); err != nil {
-		return errors.Join(ErrInitContainer, err)
+	plugins, err := c.orderPlugins()
+	if err != nil {
+		return err
 	}
-	c.plugins.Range(func(key any, value any) bool {
-		plugin := value.(*Plugin)
+	for _, plugin := range plugins {
 		if err = c.openPlugin(plugin); err != nil {
-			return false
-		}
-		if err = c.initPlugin(plugin); err != nil {
-			return false
+			return err
 		}
+		initErr := c.initPlugin(plugin)
 		if err = c.closePlugin(plugin); err != nil {
-			return false
+			return err
 		}
-		return true
-	})
-	return err
+		if initErr != nil {
+			return initErr
+		}
+		c.lifecycle.Lock()
+		c.initialized = append(c.initialized, plugin)
+		c.lifecycle.Unlock()
+	}
+	c.addPluginRules()
+	if err = c.core.Init(); err != nil {
+		return errors.Join(ErrInitContainer, err)
+	}
+	return nil
 }
 
-// Start initiates the services, ensuring they are running and managing their lifecycle.
+// Start initiates the services, ensuring they are running and managing their lifecycle, then runs the OnStart
+// functions of the initialized plugins in dependency order. If a plugin fails to start, the plugins already
+// started are stopped again and the error names the plugin.
 func (c *Core) Start() error {
-	return c.core.Start()
+	if err := c.core.Start(); err != nil {
+		return err
+	}
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	for _, plugin := range c.initialized {
+		if slices.Contains(c.started, plugin) {
+			continue
+		}
+		if err := plugin.start(); err != nil {
+			c.stopPlugins()
+			return fmt.Errorf("start %s failed: %w", plugin, err)
+		}
+		c.started = append(c.started, plugin)
+	}
+	return nil
 }
 
-// Shutdown stops all running services and cleans up resources, finalizing the Core.
+// Shutdown runs the OnStop functions of the started plugins in reverse dependency order, stops all running
+// services and cleans up resources, then runs the OnDestroy functions of the initialized plugins in reverse
+// dependency order, finalizing the Core. Hook errors are logged; calls after the first do nothing.
 func (c *Core) Shutdown() {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	c.stopPlugins()
 	c.core.Shutdown()
+	for i := len(c.initialized) - 1; i >= 0; i-- {
+		plugin := c.initialized[i]
+		if err := plugin.destroy(); err != nil {
+			util.Logger().Error("Shutdown", zap.Error(fmt.Errorf("destroy %s failed: %w", plugin, err)))
+		}
+	}
+	c.initialized = nil
 }
 
 // RegisterExtension registers a factory function with the given property, setting extension and namespace tags.
+// Extensions registered by the init functions of a plugin belong to the plugin's namespace; all others
+// belong to MainNamespace and must be registered before Init.
 func (c *Core) RegisterExtension(fn any, prop *object.Property) (*object.Definition, error) {
+	return c.registerExtension(prop, func(prop *object.Property) (*object.Definition, error) {
+		return object.ParseDefinition(fn, prop)
+	})
+}
+
+// RegisterExtensionInstance registers an existing instance as the extension typ, such as a proxy created at
+// runtime, like RegisterExtension registers a factory function. Every call yields a distinct definition.
+func (c *Core) RegisterExtensionInstance(typ reflect.Type, ins any, prop *object.Property) (*object.Definition, error) {
+	return c.registerExtension(prop, func(prop *object.Property) (*object.Definition, error) {
+		return object.ParseInstance(typ, ins, prop)
+	})
+}
+
+// registerExtension registers the extension parsed with the given property in the namespace of the current
+// plugin, or in MainNamespace.
+func (c *Core) registerExtension(prop *object.Property, parse extensionParser) (*object.Definition, error) {
+	c.mutex.RLock()
+	plugin := c.current
+	c.mutex.RUnlock()
+	if plugin != nil {
+		return c.registerPluginExt(parse, prop, plugin)
+	}
 	if err := c.checkReadonlyMode(); err != nil {
 		return nil, err
 	}
-	if plugin := c.current; plugin != nil {
-		return c.registerPluginExt(fn, prop, plugin)
-	}
-	return c.registerMainExt(fn, prop)
+	return c.registerMainExt(parse, prop)
 }
 
-// RegisterAbility registers a new ability with the given function and property, returning its definition or an error.
+// RegisterAbility registers the factory of an ability, a func(O, E) T creating the Ability T of a target O
+// from the extension E of the namespace. Abilities are created per target by GetAbility rather than by the
+// container. Abilities registered by the init functions of a plugin belong to the plugin's namespace.
 func (c *Core) RegisterAbility(fn any, prop *object.Property) (*object.Definition, error) {
-	if err := c.checkReadonlyMode(); err != nil {
-		return nil, err
+	c.mutex.RLock()
+	plugin := c.current
+	c.mutex.RUnlock()
+	if plugin == nil {
+		if err := c.checkReadonlyMode(); err != nil {
+			return nil, err
+		}
 	}
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	prop.SetTags(TagAbilityKind, newNamespaceTag(MainNamespace))
-	def, err := c.core.RegisterFactory(fn, prop, false)
+	ns := MainNamespace
+	if plugin != nil {
+		ns = plugin.Name()
+	}
+	prop.SetTags(TagAbilityKind, newNamespaceTag(ns))
+	def, err := object.ParseDefinition(fn, prop)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("register ability failed: %w", err)
 	}
-	key := def.Name()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := abilityKeyOf(def.Factory().Func().Type())
 	c.abilities[key] = append(c.abilities[key], def)
+	if plugin != nil {
+		plugin.abilities[def.Name()] = def
+	}
 	return def, nil
 }
 
@@ -142,38 +238,54 @@ func (c *Core) RegisterPlugin(plugin *Plugin) error {
 }
 
 // registerMainExt registers an extension point with the given function and property, setting extension and main namespace tags.
-func (c *Core) registerMainExt(fn any, prop *object.Property) (*object.Definition, error) {
+func (c *Core) registerMainExt(parse extensionParser, prop *object.Property) (*object.Definition, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	prop.SetTags(TagExtensionKind, newNamespaceTag(MainNamespace))
-	def, err := c.core.RegisterFactory(fn, prop, true)
+	def, err := parse(prop)
 	if err != nil {
 		return nil, fmt.Errorf("register main extension failed: %w", err)
 	}
 	if def0, ok := c.extensions[def.Name()]; ok {
-		err = fmt.Errorf("main extension %s already exists: %s", def.Name(), def0.Name())
-		return nil, err
+		if _, multi := priorityOf(def); !multi {
+			err = fmt.Errorf("main extension %s already exists: %s", def.Name(), def0.ID())
+			return nil, err
+		}
+	}
+	// plugins register the same name in their own namespaces, so the name is only unique within main
+	if err := c.core.RegisterDefinition(def, false); err != nil {
+		return nil, fmt.Errorf("register main extension failed: %w", err)
+	}
+	if _, ok := c.extensions[def.Name()]; !ok {
+		c.extensions[def.Name()] = def
 	}
-	c.extensions[def.Name()] = def
 	return def, nil
 }
 
 // registerPluginExt registers an extension for a plugin, setting appropriate tags and associating it with the plugin.
-func (c *Core) registerPluginExt(fn any, prop *object.Property, plugin *Plugin) (*object.Definition, error) {
+func (c *Core) registerPluginExt(parse extensionParser, prop *object.Property, plugin *Plugin) (*object.Definition, error) {
 	prop.SetTags(TagExtensionKind, newNamespaceTag(plugin.Name()))
-	def, err := c.core.RegisterFactory(fn, prop, false)
+	def, err := parse(prop)
 	if err != nil {
 		return nil, fmt.Errorf("register plugin extension failed: %w", err)
 	}
-	if plugin != nil {
-		if ok := plugin.addExtension(def); !ok {
-			err := fmt.Errorf("plugin extension %s already exists", def.Name())
-			return nil, err
-		}
+	if _, multi := priorityOf(def); plugin.GetExtension(def.Name()) != nil && !multi {
+		return nil, fmt.Errorf("plugin extension %s already exists", def.Name())
+	}
+	if err := c.core.RegisterDefinition(def, false); err != nil {
+		return nil, fmt.Errorf("register plugin extension failed: %w", err)
 	}
+	plugin.addExtension(def)
 	return def, nil
 }
 
+// addPluginRules adds the rules declared by the plugins to the default RuleResolver, in plugin name order.
+func (c *Core) addPluginRules() {
+	for _, plugin := range c.sortedPlugins() {
+		c.rules.AddRules(plugin.Rules()...)
+	}
+}
+
 // checkReadonlyMode verifies if the Core is in readonly mode and panics if true.
 func (c *Core) checkReadonlyMode() error {
 	if c.readonly.Load() {
@@ -204,10 +316,22 @@ func (c *Core) closePlugin(plugin *Plugin) error {
 	return nil
 }
 
+// stopPlugins runs the OnStop functions of the started plugins in reverse start order, logging their errors.
+// The caller must hold the lifecycle lock.
+func (c *Core) stopPlugins() {
+	for i := len(c.started) - 1; i >= 0; i-- {
+		plugin := c.started[i]
+		if err := plugin.stop(); err != nil {
+			util.Logger().Error("Shutdown", zap.Error(fmt.Errorf("stop %s failed: %w", plugin, err)))
+		}
+	}
+	c.started = nil
+}
+
 // initPlugin initializes a given plugin, ensuring it's ready for use and checking its abilities.
 func (c *Core) initPlugin(plugin *Plugin) error {
 	if err := plugin.init(); err != nil {
-		return errors.New("")
+		return fmt.Errorf("init %s failed: %w", plugin, err)
 	}
 	// check ability
 	return nil
@@ -2,7 +2,10 @@ package business
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"vortice/container"
 	"vortice/object"
@@ -173,3 +176,127 @@ func TestCoreRegistersDisallowedAfterStartAndShutdown(t *testing.T) {
 		t.Fatalf("expected ErrInReadonlyMode for RegisterAbility after shutdown, got %v", err)
 	}
 }
+
+// ---------- 非全局 Core：RegisterExtTo / RegisterPluginTo ----------
+type isoExt struct{}
+type isoExt2 struct{}
+
+func newIsoExt() *isoExt   { return &isoExt{} }
+func newIsoExt2() *isoExt2 { return &isoExt2{} }
+
+func TestRegisterExtTo_Isolated(t *testing.T) {
+	c := newCore()
+	RegisterExtTo0(c, newIsoExt)
+	RegisterExtTo0(c, newIsoExt2)
+	RegisterPluginTo(c, NewPlugin("iso"))
+	if _, ok := c.plugins.Load("iso"); !ok {
+		t.Fatalf("plugin should be registered with the given core")
+	}
+	if len(c.Container().GetDefinitions()) != 2 {
+		t.Fatalf("extensions should be registered with the given core")
+	}
+	if defs := DefaultCore().Container().GetDefinitionsByName(object.GenerateDefinitionName(reflect.TypeOf(&isoExt{}))); len(defs) != 0 {
+		t.Fatalf("extensions must not leak into the default core")
+	}
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+}
+
+// 插件生命周期钩子按依赖顺序启动，逆序停止与销毁
+func TestCorePluginLifecycleOrder(t *testing.T) {
+	c := newCore()
+	var calls []string
+	add := func(name string, opts ...PluginOption) {
+		p := NewPlugin(name, opts...)
+		p.OnStart(func() error {
+			calls = append(calls, "start "+name)
+			return nil
+		})
+		p.OnStop(func() error {
+			calls = append(calls, "stop "+name)
+			return nil
+		})
+		p.OnDestroy(func() error {
+			calls = append(calls, "destroy "+name)
+			return nil
+		})
+		RegisterPluginTo(c, p)
+	}
+	add("acme", WithParent("retail"))
+	add("retail")
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	c.Shutdown()
+	c.Shutdown() // 第二次调用不应重复执行钩子
+	want := []string{"start retail", "start acme", "stop acme", "stop retail", "destroy acme", "destroy retail"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("expected %v, got %v", want, calls)
+	}
+}
+
+// 启动失败时错误包含插件名与原始错误，已启动的插件被停止
+func TestCorePluginStartError(t *testing.T) {
+	c := newCore()
+	var calls []string
+	good := NewPlugin("a", WithVersion("1.0.0"))
+	good.OnStart(func() error { return nil })
+	good.OnStop(func() error {
+		calls = append(calls, "stop a")
+		return nil
+	})
+	cause := errors.New("port in use")
+	bad := NewPlugin("b", WithRequire("a", "*"))
+	bad.OnStart(func() error { return cause })
+	bad.OnStop(func() error {
+		calls = append(calls, "stop b")
+		return nil
+	})
+	RegisterPluginTo(c, good)
+	RegisterPluginTo(c, NewPlugin("c"))
+	RegisterPluginTo(c, bad)
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	err := c.Start()
+	if !errors.Is(err, cause) || !strings.Contains(err.Error(), "start <Plugin b> failed") {
+		t.Fatalf("start error should wrap the cause and name the plugin, got %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"stop a"}) {
+		t.Fatalf("started plugins should be stopped, got %v", calls)
+	}
+	c.Shutdown()
+	if !reflect.DeepEqual(calls, []string{"stop a"}) {
+		t.Fatalf("stopped plugins must not be stopped again, got %v", calls)
+	}
+}
+
+// 停止与销毁钩子出错不影响其他钩子执行
+func TestCorePluginStopErrorsContinue(t *testing.T) {
+	c := newCore()
+	var calls []string
+	p := NewPlugin("p")
+	p.OnStop(func() error { return errors.New("stop failed") }, func() error {
+		calls = append(calls, "stop")
+		return nil
+	})
+	p.OnDestroy(func() error { return errors.New("destroy failed") }, func() error {
+		calls = append(calls, "destroy")
+		return nil
+	})
+	RegisterPluginTo(c, p)
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	c.Shutdown()
+	if !reflect.DeepEqual(calls, []string{"stop", "destroy"}) {
+		t.Fatalf("expected all hooks to run, got %v", calls)
+	}
+}
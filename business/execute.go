@@ -0,0 +1,137 @@
+package business
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"vortice/container"
+	"vortice/object"
+)
+
+var (
+	// ErrExtensionNotFound is the error returned when no namespace of the resolution chain implements an extension point.
+	ErrExtensionNotFound = errors.New("extension not found")
+)
+
+// Execute calls fn with the extension E of the default Core for the namespace of ctx, falling back to
+// MainNamespace when the namespace does not implement E, and returns its result.
+/*
+	type PriceCalculator interface{ Price(order *Order) int }
+	ctx := business.WithContext(context.Background(), "vip")
+	price, err := business.Execute(ctx, func(c PriceCalculator) int { return c.Price(order) })
+*/
+func Execute[E, R any](ctx context.Context, fn func(E) R) (R, error) {
+	return ExecuteWith(DefaultCore(), ctx, fn)
+}
+
+// ExecuteWith calls fn with the extension E of the given Core for the namespace of ctx, falling back to
+// MainNamespace when the namespace does not implement E, and returns its result. A plugin disabled with
+// DisablePlugin while fn runs its extension waits for fn to return.
+func ExecuteWith[E, R any](c *Core, ctx context.Context, fn func(E) R) (R, error) {
+	ext, leave, err := enterExtension[E](c, ctx)
+	if err != nil {
+		var zero R
+		return zero, err
+	}
+	defer leave()
+	return fn(ext), nil
+}
+
+// GetExtension returns the extension E of the given Core for the namespace of ctx, falling back to
+// MainNamespace when the namespace does not implement E. Among several extensions of one namespace,
+// the one with the highest priority is returned. Unlike ExecuteWith, the use of the returned extension is not
+// tracked, so DisablePlugin does not wait for it.
+func GetExtension[E any](c *Core, ctx context.Context) (E, error) {
+	ext, leave, err := enterExtension[E](c, ctx)
+	if err == nil {
+		leave()
+	}
+	return ext, err
+}
+
+// enterExtension resolves the extension E like GetExtension and registers a call in flight in its namespace,
+// returning the function ending the call. Namespaces disabled after the chain was resolved are skipped.
+func enterExtension[E any](c *Core, ctx context.Context) (E, func(), error) {
+	var zero E
+	name := object.GenerateDefinitionName(reflect.TypeOf((*E)(nil)).Elem())
+	chain := c.ResolutionChainOf(ctx)
+	for _, ns := range chain {
+		objs, err := c.extensionObjects(ctx, ns, name)
+		if err != nil {
+			return zero, nil, err
+		}
+		if len(objs) == 0 {
+			continue
+		}
+		leave, ok := c.enterNamespace(ns)
+		if !ok {
+			continue
+		}
+		ext, err := extensionOf[E](objs[0])
+		if err != nil {
+			leave()
+			return zero, nil, err
+		}
+		return ext, leave, nil
+	}
+	return zero, nil, fmt.Errorf("%w: %s in namespaces %s", ErrExtensionNotFound, name, strings.Join(chain, ", "))
+}
+
+// ResolutionChain returns the namespaces searched, in order, for an extension requested in the namespace:
+// the namespace itself, the ancestors declared with WithParent, and MainNamespace. An empty namespace
+// resolves to MainNamespace only, and the namespaces of disabled plugins are left out.
+func (c *Core) ResolutionChain(ns string) []string {
+	var chain, seen []string
+	for ns != "" && ns != MainNamespace && !slices.Contains(seen, ns) {
+		seen = append(seen, ns)
+		value, ok := c.plugins.Load(ns)
+		if !ok {
+			chain = append(chain, ns)
+			break
+		}
+		plugin := value.(*Plugin)
+		if plugin.gate.enabled() {
+			chain = append(chain, ns)
+		}
+		ns = plugin.Parent()
+	}
+	return append(chain, MainNamespace)
+}
+
+// extensionObjects returns the objects of the extensions named name registered in the namespace, highest
+// priority first and in registration order for equal priorities.
+func (c *Core) extensionObjects(ctx context.Context, ns, name string) ([]container.Object, error) {
+	coreCtx := container.WithCoreContext(ctx)
+	coreCtx.SetFilter(object.TagFilter(TagExtensionKind), object.TagFilter(newNamespaceTag(ns)))
+	objs, err := c.core.GetObjectsByName(coreCtx, name)
+	if err != nil {
+		return nil, err
+	}
+	sortByPriority(objs)
+	return objs, nil
+}
+
+// sortByPriority sorts extension objects by descending priority, keeping the order of equal priorities.
+func sortByPriority(objs []container.Object) {
+	slices.SortStableFunc(objs, func(a, b container.Object) int {
+		pa, _ := priorityOf(a.Definition())
+		pb, _ := priorityOf(b.Definition())
+		return cmp.Compare(pb, pa)
+	})
+}
+
+// extensionOf returns the instance of the extension object as E.
+func extensionOf[E any](obj container.Object) (E, error) {
+	ext, ok := obj.Instance().(E)
+	if !ok {
+		var zero E
+		return zero, fmt.Errorf("extension %s is %T, not %s", obj.Definition().ID(), obj.Instance(),
+			reflect.TypeOf((*E)(nil)).Elem())
+	}
+	return ext, nil
+}
@@ -0,0 +1,176 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"vortice/object"
+)
+
+type greeter interface{ Greet(name string) string }
+type farewell interface{ Bye() string }
+
+type mainGreeter struct{}
+type vipGreeter struct{}
+type mainFarewell struct{}
+
+func (mainGreeter) Greet(name string) string { return "hello " + name }
+func (vipGreeter) Greet(name string) string  { return "welcome back " + name }
+func (mainFarewell) Bye() string             { return "bye" }
+
+func newMainGreeter() greeter   { return mainGreeter{} }
+func newVipGreeter() greeter    { return vipGreeter{} }
+func newMainFarewell() farewell { return mainFarewell{} }
+
+// 构造带 main 扩展与 vip 插件扩展的 Core
+func newExecuteCore(t *testing.T) *Core {
+	t.Helper()
+	c := newCore()
+	RegisterExtTo0(c, newMainGreeter)
+	RegisterExtTo0(c, newMainFarewell)
+	vip := NewPlugin("vip")
+	vip.Init(func() error {
+		RegisterExtTo0(c, newVipGreeter)
+		return nil
+	})
+	RegisterPluginTo(c, vip)
+	RegisterPluginTo(c, NewPlugin("empty"))
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	return c
+}
+
+func TestExecuteWith_NamespaceRouting(t *testing.T) {
+	c := newExecuteCore(t)
+	greet := func(g greeter) string { return g.Greet("bob") }
+	cases := map[string]string{
+		"vip":         "welcome back bob",
+		"empty":       "hello bob", // 插件未实现，回退到 main
+		MainNamespace: "hello bob",
+		"unknown":     "hello bob",
+	}
+	for ns, want := range cases {
+		got, err := ExecuteWith(c, WithContext(context.Background(), ns), greet)
+		if err != nil || got != want {
+			t.Fatalf("namespace %s: expected %q, got %q %v", ns, want, got, err)
+		}
+	}
+	// 无命名空间的普通 context 使用 main
+	if got, err := ExecuteWith(c, context.Background(), greet); err != nil || got != "hello bob" {
+		t.Fatalf("plain context should use main, got %q %v", got, err)
+	}
+	// 插件只覆盖自己实现的扩展点
+	if got, err := ExecuteWith(c, WithContext(context.Background(), "vip"), farewell.Bye); err != nil || got != "bye" {
+		t.Fatalf("vip should fall back to main farewell, got %q %v", got, err)
+	}
+}
+
+func TestExecuteWith_NotFound(t *testing.T) {
+	c := newCore()
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	_, err := ExecuteWith(c, WithContext(context.Background(), "vip"), func(g greeter) string { return "" })
+	if !errors.Is(err, ErrExtensionNotFound) || !strings.Contains(err.Error(), "vip, main") {
+		t.Fatalf("expected ErrExtensionNotFound naming the chain, got %v", err)
+	}
+}
+
+// 插件 init 中注册的扩展带有插件命名空间，并记录在插件中
+func TestPluginInitRegistersExtensions(t *testing.T) {
+	c := newExecuteCore(t)
+	value, _ := c.plugins.Load("vip")
+	def := value.(*Plugin).GetExtension(object.GenerateDefinitionName(reflect.TypeOf((*greeter)(nil)).Elem()))
+	if def == nil || !object.TagFilter(newNamespaceTag("vip"))(def) {
+		t.Fatalf("vip extension should be registered in the vip namespace, got %v", def)
+	}
+}
+
+func TestPluginInitErrorWrapped(t *testing.T) {
+	c := newCore()
+	p := NewPlugin("broken")
+	cause := errors.New("boom")
+	p.Init(func() error { return cause })
+	RegisterPluginTo(c, p)
+	err := c.Init()
+	if !errors.Is(err, cause) || !strings.Contains(err.Error(), "<Plugin broken>") {
+		t.Fatalf("init error should wrap the cause and name the plugin, got %v", err)
+	}
+}
+
+type retailGreeter struct{}
+
+func (retailGreeter) Greet(name string) string { return "dear customer " + name }
+
+func newRetailGreeter() greeter { return retailGreeter{} }
+
+// 插件声明父插件后，解析链为 插件 -> 父插件 -> main
+func TestExecuteWith_ParentChain(t *testing.T) {
+	c := newCore()
+	RegisterExtTo0(c, newMainGreeter)
+	RegisterExtTo0(c, newMainFarewell)
+	retail := NewPlugin("retail")
+	retail.Init(func() error {
+		RegisterExtTo0(c, newRetailGreeter)
+		return nil
+	})
+	RegisterPluginTo(c, retail)
+	RegisterPluginTo(c, NewPlugin("acme", WithParent("retail")))
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if chain := c.ResolutionChain("acme"); !reflect.DeepEqual(chain, []string{"acme", "retail", MainNamespace}) {
+		t.Fatalf("unexpected resolution chain %v", chain)
+	}
+	ctx := WithContext(context.Background(), "acme")
+	if got, err := ExecuteWith(c, ctx, func(g greeter) string { return g.Greet("bob") }); err != nil || got != "dear customer bob" {
+		t.Fatalf("acme should inherit the retail greeter, got %q %v", got, err)
+	}
+	if got, err := ExecuteWith(c, ctx, farewell.Bye); err != nil || got != "bye" {
+		t.Fatalf("acme should fall back to main farewell, got %q %v", got, err)
+	}
+}
+
+func TestCoreInit_PluginParentErrors(t *testing.T) {
+	c := newCore()
+	RegisterPluginTo(c, NewPlugin("a", WithParent("b")))
+	RegisterPluginTo(c, NewPlugin("b", WithParent("a")))
+	RegisterPluginTo(c, NewPlugin("orphan", WithParent("missing")))
+	err := c.Init()
+	if err == nil || !strings.Contains(err.Error(), "plugin cycle detected") ||
+		!strings.Contains(err.Error(), "parent plugin missing is not registered") {
+		t.Fatalf("expected cycle and missing parent errors, got %v", err)
+	}
+	// 即使存在环，解析链也应终止
+	if chain := c.ResolutionChain("a"); !reflect.DeepEqual(chain, []string{"a", "b", MainNamespace}) {
+		t.Fatalf("unexpected resolution chain %v", chain)
+	}
+}
+
+// 以实例注册的扩展与工厂函数注册的扩展一样参与解析
+func TestRegisterExtensionInstance(t *testing.T) {
+	c := newCore()
+	RegisterExtTo0(c, newMainGreeter)
+	typ := reflect.TypeOf((*greeter)(nil)).Elem()
+	for _, ns := range []string{"a", "b"} {
+		p := NewPlugin(ns)
+		p.Init(func() error {
+			_, err := c.RegisterExtensionInstance(typ, vipGreeter{}, object.NewProperty())
+			return err
+		})
+		RegisterPluginTo(c, p)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	for _, ns := range []string{"a", "b"} {
+		got, err := ExecuteWith(c, WithContext(context.Background(), ns), func(g greeter) string { return g.Greet("bob") })
+		if err != nil || got != "welcome back bob" {
+			t.Fatalf("namespace %s: expected the instance, got %q %v", ns, got, err)
+		}
+	}
+}
@@ -1,12 +1,18 @@
 package business
 
 import (
+	"strconv"
+
 	"vortice/container"
 	"vortice/object"
 )
 
-// TagBizKindKey is a constant string used to identify the business kind in tagged data.
-const TagBizKindKey = "kind"
+const (
+	// TagBizKindKey is a constant string used to identify the business kind in tagged data.
+	TagBizKindKey = "kind"
+	// TagPriorityKey is the key of the tag holding the priority declared with WithPriority.
+	TagPriorityKey = "priority"
+)
 
 var (
 	// TagExtensionKind is a predefined Tag used to mark components or properties as extensions with the key "biz_kind" and value "extension".
@@ -29,3 +35,23 @@ type (
 func newExtensionObject(obj container.Object) *ExtensionObject {
 	return &ExtensionObject{Object: obj}
 }
+
+// WithPriority declares the priority of an extension: extensions with a higher priority run first in
+// ExecuteAll and are preferred by Execute. Extensions declaring a priority may share their type with other
+// extensions of the same namespace, which makes the extension point multi-contributor; the default is 0.
+func WithPriority(priority int) Option {
+	return func(prop *object.Property) {
+		prop.SetTags(object.NewTag(TagPriorityKey, strconv.Itoa(priority)))
+	}
+}
+
+// priorityOf returns the priority declared by the definition and whether it declared one.
+func priorityOf(def *object.Definition) (int, bool) {
+	for _, tag := range def.Tags() {
+		if tag.Key() == TagPriorityKey {
+			priority, err := strconv.Atoi(tag.Value())
+			return priority, err == nil
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,176 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"vortice/object"
+)
+
+type (
+	// Attributes are the request attributes a business identity is resolved from, such as tenant, channel,
+	// product line or region.
+	Attributes map[string]string
+	// BizIdentityResolver resolves request attributes to a namespace chain, most specific namespace first.
+	// MainNamespace is implicitly the last namespace of every chain.
+	BizIdentityResolver interface {
+		// Resolve returns the namespace chain of the attributes, or false if no namespace applies.
+		Resolve(ctx context.Context, attrs Attributes) ([]string, bool)
+	}
+	// Rule maps the requests whose attributes all equal those of Match to Namespace.
+	Rule struct {
+		// Namespace is the namespace of matching requests.
+		Namespace string
+		// Match holds the attribute values a request must have.
+		Match Attributes
+		// Priority orders matching rules; for equal priorities, rules matching more attributes come first.
+		Priority int
+	}
+	// RuleResolver is a BizIdentityResolver whose chain is made of the namespaces of every matching rule,
+	// ordered by descending priority, then by the number of matched attributes, then by declaration order.
+	RuleResolver struct {
+		mutex *sync.RWMutex
+		rules []Rule
+	}
+)
+
+// namespacesKey is the context key of the namespace chain installed by WithNamespaces.
+var namespacesKey = ctxKey("namespaces")
+
+// String returns a description of the rule such as "channel=app&tenant=acme -> acme-app".
+func (r Rule) String() string {
+	conds := make([]string, 0, len(r.Match))
+	for k, v := range r.Match {
+		conds = append(conds, k+"="+v)
+	}
+	sort.Strings(conds)
+	return fmt.Sprintf("%s -> %s", strings.Join(conds, "&"), r.Namespace)
+}
+
+// Matches returns true if every attribute of the rule has the same value in attrs.
+func (r Rule) Matches(attrs Attributes) bool {
+	for k, v := range r.Match {
+		if got, ok := attrs[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// NewRuleResolver creates a RuleResolver with the given rules.
+func NewRuleResolver(rules ...Rule) *RuleResolver {
+	return &RuleResolver{mutex: &sync.RWMutex{}, rules: append([]Rule{}, rules...)}
+}
+
+// AddRules appends rules to the resolver.
+func (r *RuleResolver) AddRules(rules ...Rule) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.rules = append(r.rules, rules...)
+}
+
+// Rules returns the rules of the resolver in declaration order.
+func (r *RuleResolver) Rules() []Rule {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return append([]Rule{}, r.rules...)
+}
+
+// Resolve returns the namespaces of the matching rules, each namespace once.
+func (r *RuleResolver) Resolve(_ context.Context, attrs Attributes) ([]string, bool) {
+	var matched []Rule
+	for _, rule := range r.Rules() {
+		if rule.Matches(attrs) {
+			matched = append(matched, rule)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].Priority != matched[j].Priority {
+			return matched[i].Priority > matched[j].Priority
+		}
+		return len(matched[i].Match) > len(matched[j].Match)
+	})
+	var chain []string
+	for _, rule := range matched {
+		if !slices.Contains(chain, rule.Namespace) {
+			chain = append(chain, rule.Namespace)
+		}
+	}
+	return chain, len(chain) > 0
+}
+
+// WithNamespaces creates a context for the namespace chain, most specific first. Namespace returns the first
+// namespace of the chain, the definition filter matches every namespace of the chain, and extensions are
+// resolved along the whole chain before MainNamespace.
+func WithNamespaces(ctx context.Context, namespaces ...string) Context {
+	if len(namespaces) == 0 {
+		return WithContext(ctx, MainNamespace)
+	}
+	ctx = context.WithValue(ctx, namespacesKey, append([]string{}, namespaces...))
+	bizCtx := WithContext(ctx, namespaces[0])
+	tags := make([]object.Tag, 0, len(namespaces))
+	for _, ns := range namespaces {
+		tags = append(tags, newNamespaceTag(ns))
+	}
+	bizCtx.SetFilter(object.TagFilter(tags...))
+	return bizCtx
+}
+
+// GetNamespaces returns the namespace chain of the context: the chain installed by WithNamespaces, the
+// namespace installed by WithContext, or nil.
+func GetNamespaces(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	if namespaces, ok := ctx.Value(namespacesKey).([]string); ok {
+		return append([]string{}, namespaces...)
+	}
+	if ns := GetNamespace(ctx); ns != "" {
+		return []string{ns}
+	}
+	return nil
+}
+
+// SetIdentityResolver replaces the resolver used by ResolveContext. By default, the Core uses a RuleResolver
+// holding the rules declared by its plugins.
+func (c *Core) SetIdentityResolver(resolver BizIdentityResolver) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.resolver = resolver
+}
+
+// ResolveContext resolves the attributes to a namespace chain and returns a Context for it, or a Context of
+// MainNamespace if no namespace applies. Use it in middlewares and interceptors installing the business identity.
+func (c *Core) ResolveContext(ctx context.Context, attrs Attributes) Context {
+	c.mutex.RLock()
+	resolver := c.resolver
+	c.mutex.RUnlock()
+	if chain, ok := resolver.Resolve(ctx, attrs); ok {
+		return WithNamespaces(ctx, chain...)
+	}
+	return WithContext(ctx, MainNamespace)
+}
+
+// ResolutionChainOf returns the namespaces searched for an extension requested with ctx: the resolution chain
+// of every namespace of the context, in order and each namespace once, ending with MainNamespace.
+func (c *Core) ResolutionChainOf(ctx context.Context) []string {
+	namespaces := GetNamespaces(ctx)
+	if len(namespaces) == 0 {
+		return c.ResolutionChain("")
+	}
+	var chain []string
+	for _, ns := range namespaces {
+		for _, n := range c.ResolutionChain(ns) {
+			if !slices.Contains(chain, n) {
+				chain = append(chain, n)
+			}
+		}
+	}
+	// MainNamespace always comes last, after every namespace of the context
+	chain = slices.DeleteFunc(chain, func(n string) bool { return n == MainNamespace })
+	return append(chain, MainNamespace)
+}
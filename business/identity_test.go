@@ -0,0 +1,141 @@
+package business
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// 更具体的规则排在前面：acme-app -> acme
+func TestRuleResolver_Chain(t *testing.T) {
+	r := NewRuleResolver(
+		Rule{Namespace: "acme", Match: Attributes{"tenant": "acme"}},
+		Rule{Namespace: "acme-app", Match: Attributes{"tenant": "acme", "channel": "app"}},
+		Rule{Namespace: "eu", Match: Attributes{"region": "eu"}},
+	)
+	chain, ok := r.Resolve(context.Background(), Attributes{"tenant": "acme", "channel": "app"})
+	if !ok || !reflect.DeepEqual(chain, []string{"acme-app", "acme"}) {
+		t.Fatalf("unexpected chain %v", chain)
+	}
+	chain, _ = r.Resolve(context.Background(), Attributes{"tenant": "acme", "channel": "web", "region": "eu"})
+	if !reflect.DeepEqual(chain, []string{"acme", "eu"}) {
+		t.Fatalf("equally specific rules should keep declaration order, got %v", chain)
+	}
+	if _, ok := r.Resolve(context.Background(), Attributes{"tenant": "other"}); ok {
+		t.Fatalf("no rule should match")
+	}
+}
+
+func TestRuleResolver_Priority(t *testing.T) {
+	r := NewRuleResolver(
+		Rule{Namespace: "acme-app", Match: Attributes{"tenant": "acme", "channel": "app"}},
+		Rule{Namespace: "eu", Match: Attributes{"region": "eu"}, Priority: 1},
+	)
+	chain, _ := r.Resolve(context.Background(), Attributes{"tenant": "acme", "channel": "app", "region": "eu"})
+	if !reflect.DeepEqual(chain, []string{"eu", "acme-app"}) {
+		t.Fatalf("higher priority should come first, got %v", chain)
+	}
+	if s := r.Rules()[0].String(); s != "channel=app&tenant=acme -> acme-app" {
+		t.Fatalf("unexpected rule string %s", s)
+	}
+}
+
+// 插件声明的规则在 Init 时收集，Execute 沿命名空间链回退
+func newIdentityCore(t *testing.T) *Core {
+	t.Helper()
+	c := newCore()
+	RegisterExtTo0(c, newMainGreeter)
+	RegisterExtTo0(c, newMainFarewell)
+	acme := NewPlugin("acme")
+	acme.Match(Attributes{"tenant": "acme"})
+	acme.Init(func() error {
+		RegisterExtTo0(c, newVipGreeter)
+		return nil
+	})
+	acmeApp := NewPlugin("acme-app")
+	acmeApp.Match(Attributes{"tenant": "acme", "channel": "app"})
+	RegisterPluginTo(c, acme)
+	RegisterPluginTo(c, acmeApp)
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	return c
+}
+
+func TestCore_ResolveContext(t *testing.T) {
+	c := newIdentityCore(t)
+	ctx := c.ResolveContext(context.Background(), Attributes{"tenant": "acme", "channel": "app"})
+	if ctx.Namespace() != "acme-app" || !reflect.DeepEqual(GetNamespaces(ctx), []string{"acme-app", "acme"}) {
+		t.Fatalf("unexpected context %s %v", ctx.Namespace(), GetNamespaces(ctx))
+	}
+	if chain := c.ResolutionChainOf(ctx); !reflect.DeepEqual(chain, []string{"acme-app", "acme", MainNamespace}) {
+		t.Fatalf("unexpected resolution chain %v", chain)
+	}
+	// acme-app 未实现 greeter，回退到 acme
+	if got, err := ExecuteWith(c, ctx, func(g greeter) string { return g.Greet("ann") }); err != nil || got != "welcome back ann" {
+		t.Fatalf("expected the acme greeter, got %q %v", got, err)
+	}
+	if got, err := ExecuteWith(c, ctx, farewell.Bye); err != nil || got != "bye" {
+		t.Fatalf("expected the main farewell, got %q %v", got, err)
+	}
+	if ctx := c.ResolveContext(context.Background(), Attributes{"tenant": "nobody"}); ctx.Namespace() != MainNamespace {
+		t.Fatalf("unmatched attributes should resolve to main, got %s", ctx.Namespace())
+	}
+}
+
+type staticResolver []string
+
+func (s staticResolver) Resolve(context.Context, Attributes) ([]string, bool) { return s, true }
+
+func TestCore_SetIdentityResolver(t *testing.T) {
+	c := newCore()
+	c.SetIdentityResolver(staticResolver{"fixed"})
+	if ctx := c.ResolveContext(context.Background(), nil); ctx.Namespace() != "fixed" {
+		t.Fatalf("custom resolver should be used, got %s", ctx.Namespace())
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	c := newIdentityCore(t)
+	var got string
+	handler := HTTPMiddleware(c, HeaderAttributes(map[string]string{"tenant": "X-Tenant", "channel": "X-Channel"}))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = ExecuteWith(c, r.Context(), func(g greeter) string { return g.Greet("joe") })
+		}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant", "acme")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "welcome back joe" {
+		t.Fatalf("handler should see the acme namespace, got %q", got)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got != "hello joe" {
+		t.Fatalf("requests without attributes should use main, got %q", got)
+	}
+}
+
+// 模拟 gRPC 拦截器：从传入的 metadata 解析业务身份
+func TestMetadataContext(t *testing.T) {
+	c := newIdentityCore(t)
+	keys := map[string]string{"tenant": "x-tenant", "channel": "x-channel"}
+	interceptor := func(ctx context.Context, md map[string][]string, handler func(ctx context.Context) string) string {
+		return handler(MetadataContext(c, ctx, keys, func(key string) string {
+			if v := md[key]; len(v) > 0 {
+				return v[0]
+			}
+			return ""
+		}))
+	}
+	handler := func(ctx context.Context) string {
+		got, _ := ExecuteWith(c, ctx, func(g greeter) string { return g.Greet("joe") })
+		return got
+	}
+	if got := interceptor(context.Background(), map[string][]string{"x-tenant": {"acme"}}, handler); got != "welcome back joe" {
+		t.Fatalf("handler should see the acme namespace, got %q", got)
+	}
+	if got := interceptor(context.Background(), nil, handler); got != "hello joe" {
+		t.Fatalf("calls without metadata should use main, got %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ABIVersion is the version of the contract between the host and plugins loaded with LoadPluginFile. It changes
+// whenever a change to Core, Plugin or PluginEntry breaks plugins built against an earlier version.
+const ABIVersion = "1"
+
+// PluginSymbol is the name of the PluginEntry variable a plugin shared object must export.
+const PluginSymbol = "VorticePlugin"
+
+var (
+	// ErrPluginsUnsupported is the error returned by LoadPluginFile on platforms without Go plugin support.
+	ErrPluginsUnsupported = errors.New("plugin shared objects are not supported on this platform")
+	// ErrABIMismatch is the error returned when a plugin shared object was built against another ABIVersion.
+	ErrABIMismatch = errors.New("plugin ABI version mismatch")
+)
+
+// PluginEntry is the entry point of a plugin shared object, built with -buildmode=plugin and exported as
+// a package-level variable named VorticePlugin:
+/*
+	package main
+
+	var VorticePlugin = business.PluginEntry{
+		ABI: business.ABIVersion,
+		New: func(c *business.Core) *business.Plugin {
+			p := business.NewPlugin("acme", business.WithVersion("1.0.0"))
+			p.Init(func() error {
+				business.RegisterExtTo0(c, newAcmePricing)
+				return nil
+			})
+			return p
+		},
+	}
+*/
+type PluginEntry struct {
+	// ABI is the ABIVersion the plugin was built against.
+	ABI string
+	// New creates the plugin for the Core loading it. Its init functions register the plugin's extensions
+	// into the plugin's namespace when the Core initializes.
+	New func(c *Core) *Plugin
+}
+
+// LoadPluginFile loads a plugin shared object into the default core. See Core.LoadPluginFile.
+func LoadPluginFile(path string) (*Plugin, error) {
+	return DefaultCore().LoadPluginFile(path)
+}
+
+// LoadPluginDir loads every plugin shared object of a directory into the default core. See Core.LoadPluginDir.
+func LoadPluginDir(dir string) ([]*Plugin, error) {
+	return DefaultCore().LoadPluginDir(dir)
+}
+
+// LoadPluginFile opens a plugin shared object, checks that its VorticePlugin entry was built against the
+// host's ABIVersion and registers the plugin it creates. Plugins must be loaded before Init; shared objects
+// are only supported on Linux with cgo enabled, elsewhere ErrPluginsUnsupported is returned.
+func (c *Core) LoadPluginFile(path string) (*Plugin, error) {
+	if err := c.checkReadonlyMode(); err != nil {
+		return nil, err
+	}
+	symbol, err := lookupPluginSymbol(path, PluginSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("load plugin %s failed: %w", path, err)
+	}
+	plugin, err := c.loadPluginEntry(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("load plugin %s failed: %w", path, err)
+	}
+	return plugin, nil
+}
+
+// LoadPluginDir loads every *.so file of the directory, not descending into subdirectories, in name order.
+// Files failing to load do not prevent loading the others; their errors are returned joined together with
+// the plugins that were loaded.
+func (c *Core) LoadPluginDir(dir string) ([]*Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	var (
+		plugins []*Plugin
+		errs    []error
+	)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".so") {
+			continue
+		}
+		plugin, err := c.LoadPluginFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins, errors.Join(errs...)
+}
+
+// loadPluginEntry checks the VorticePlugin symbol of a shared object and registers the plugin it creates.
+func (c *Core) loadPluginEntry(symbol any) (*Plugin, error) {
+	entry, ok := symbol.(*PluginEntry)
+	if !ok {
+		return nil, fmt.Errorf("symbol %s is %T, not %T", PluginSymbol, symbol, entry)
+	}
+	if entry.ABI != ABIVersion {
+		return nil, fmt.Errorf("%w: plugin %q, host %q", ErrABIMismatch, entry.ABI, ABIVersion)
+	}
+	if entry.New == nil {
+		return nil, fmt.Errorf("symbol %s has no New function", PluginSymbol)
+	}
+	plugin := entry.New(c)
+	if err := c.RegisterPlugin(plugin); err != nil {
+		return nil, err
+	}
+	return plugin, nil
+}
@@ -0,0 +1,14 @@
+//go:build linux && cgo
+
+package business
+
+import "plugin"
+
+// lookupPluginSymbol opens the shared object at path and looks up the named symbol.
+func lookupPluginSymbol(path, name string) (any, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return p.Lookup(name)
+}
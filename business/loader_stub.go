@@ -0,0 +1,8 @@
+//go:build !(linux && cgo)
+
+package business
+
+// lookupPluginSymbol returns ErrPluginsUnsupported, Go plugins requiring Linux with cgo enabled.
+func lookupPluginSymbol(path, name string) (any, error) {
+	return nil, ErrPluginsUnsupported
+}
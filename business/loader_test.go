@@ -0,0 +1,90 @@
+package business
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPluginEntry(t *testing.T) {
+	c := newCore()
+	entry := &PluginEntry{ABI: ABIVersion, New: func(core *Core) *Plugin {
+		if core != c {
+			t.Fatalf("New should receive the loading core")
+		}
+		return NewPlugin("shared")
+	}}
+	p, err := c.loadPluginEntry(entry)
+	if err != nil || p.Name() != "shared" {
+		t.Fatalf("load entry failed: %v %v", p, err)
+	}
+	if _, ok := c.plugins.Load("shared"); !ok {
+		t.Fatalf("plugin should be registered")
+	}
+	// 重复加载同名插件
+	if _, err := c.loadPluginEntry(entry); err == nil {
+		t.Fatalf("expected duplicate plugin error")
+	}
+}
+
+func TestLoadPluginEntry_Invalid(t *testing.T) {
+	c := newCore()
+	newPlugin := func(*Core) *Plugin { return NewPlugin("x") }
+	cases := map[string]struct {
+		symbol any
+		want   string
+	}{
+		"wrong type": {symbol: PluginEntry{ABI: ABIVersion, New: newPlugin}, want: "not *business.PluginEntry"},
+		"abi":        {symbol: &PluginEntry{ABI: "0", New: newPlugin}, want: ErrABIMismatch.Error()},
+		"no new":     {symbol: &PluginEntry{ABI: ABIVersion}, want: "has no New function"},
+	}
+	for name, tc := range cases {
+		if _, err := c.loadPluginEntry(tc.symbol); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", name, tc.want, err)
+		}
+	}
+	if _, err := c.loadPluginEntry(&PluginEntry{ABI: "0", New: newPlugin}); !errors.Is(err, ErrABIMismatch) {
+		t.Fatalf("expected ErrABIMismatch, got %v", err)
+	}
+}
+
+func TestLoadPluginFile_Errors(t *testing.T) {
+	c := newCore()
+	if _, err := c.LoadPluginFile(filepath.Join(t.TempDir(), "missing.so")); err == nil ||
+		!strings.Contains(err.Error(), "missing.so") {
+		t.Fatalf("expected error naming the file, got %v", err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, err := c.LoadPluginFile("any.so"); err != ErrInReadonlyMode {
+		t.Fatalf("expected ErrInReadonlyMode, got %v", err)
+	}
+}
+
+// 只加载目录下的 .so 文件，错误汇总返回
+func TestLoadPluginDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.so", "a.so", "README.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("not a plugin"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub.so"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plugins, err := newCore().LoadPluginDir(dir)
+	if len(plugins) != 0 || err == nil {
+		t.Fatalf("expected no plugins and an error, got %v %v", plugins, err)
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "a.so") || !strings.Contains(msg, "b.so") || strings.Index(msg, "a.so") > strings.Index(msg, "b.so") ||
+		strings.Contains(msg, "README") || strings.Contains(msg, "sub.so") {
+		t.Fatalf("expected errors for a.so and b.so in order only, got %v", err)
+	}
+	if _, err := newCore().LoadPluginDir(filepath.Join(dir, "missing")); err == nil {
+		t.Fatalf("expected error for a missing directory")
+	}
+}
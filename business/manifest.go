@@ -0,0 +1,313 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"vortice/util"
+
+	"go.uber.org/zap"
+)
+
+// ErrInvalidPlugins is the error returned by Init when the plugins' manifests, parents or dependencies are invalid.
+var ErrInvalidPlugins = errors.New("invalid plugins")
+
+// Manifest describes a plugin: its version, what it does and what it requires from the host and other plugins.
+// Versions follow semantic versioning and requirements are constraints such as "^1.2" or ">=1.0.0, <2.0.0",
+// see util.ParseConstraint.
+type Manifest struct {
+	// Version is the version of the plugin, e.g. "1.4.0".
+	Version string
+	// Description is a human-readable description of the plugin.
+	Description string
+	// Host is the constraint the host version, set with Core.SetHostVersion, must satisfy.
+	Host string
+	// Requires maps the names of the plugins the plugin depends on to the constraint their version must satisfy.
+	Requires map[string]string
+}
+
+// WithManifest sets the manifest of the plugin.
+func WithManifest(m Manifest) PluginOption {
+	return func(p *Plugin) {
+		p.manifest = m
+		p.manifest.Requires = maps.Clone(m.Requires)
+	}
+}
+
+// WithVersion sets the version of the plugin.
+func WithVersion(version string) PluginOption {
+	return func(p *Plugin) {
+		p.manifest.Version = version
+	}
+}
+
+// WithDescription sets the description of the plugin.
+func WithDescription(description string) PluginOption {
+	return func(p *Plugin) {
+		p.manifest.Description = description
+	}
+}
+
+// WithHostVersion declares the constraint the host version must satisfy.
+func WithHostVersion(constraint string) PluginOption {
+	return func(p *Plugin) {
+		p.manifest.Host = constraint
+	}
+}
+
+// WithRequire declares that the plugin depends on the named plugin, whose version must satisfy the constraint.
+// Required plugins are initialized before the plugin.
+func WithRequire(name, constraint string) PluginOption {
+	return func(p *Plugin) {
+		if p.manifest.Requires == nil {
+			p.manifest.Requires = map[string]string{}
+		}
+		p.manifest.Requires[name] = constraint
+	}
+}
+
+type (
+	// MissingPlugin describes a parent or required plugin that is not registered.
+	MissingPlugin struct {
+		// Name is the name of the missing plugin.
+		Name string
+		// Constraint is the version constraint of the requirement; it is empty for a parent.
+		Constraint string
+		// Parent is true if the plugin was declared as the parent with WithParent.
+		Parent bool
+		// RequiredBy is the plugin declaring the parent or requirement.
+		RequiredBy *Plugin
+	}
+	// VersionConflict describes a plugin, or the host, whose version does not satisfy a constraint.
+	VersionConflict struct {
+		// Name is the name of the required plugin, or empty for the host.
+		Name string
+		// Version is the version of the required plugin or host; it is empty if none was declared.
+		Version string
+		// Constraint is the version constraint of the requirement.
+		Constraint string
+		// RequiredBy is the plugin declaring the requirement.
+		RequiredBy *Plugin
+	}
+	// InvalidManifest describes a manifest with an unparsable version or constraint.
+	InvalidManifest struct {
+		// Plugin is the plugin the manifest belongs to.
+		Plugin *Plugin
+		// Err describes the problem.
+		Err error
+	}
+	// PluginCycle is a closed path of plugin names along parents and requirements, the first name repeated at the end.
+	PluginCycle []string
+)
+
+// String returns a description of the missing plugin and the plugin requiring it.
+func (m MissingPlugin) String() string {
+	if m.Parent {
+		return fmt.Sprintf("%s: parent plugin %s is not registered", m.RequiredBy, m.Name)
+	}
+	return fmt.Sprintf("%s: required plugin %s %s is not registered", m.RequiredBy, m.Name, m.Constraint)
+}
+
+// String returns a description of the version conflict.
+func (v VersionConflict) String() string {
+	name := "plugin " + v.Name
+	if v.Name == "" {
+		name = "host"
+	}
+	if v.Version == "" {
+		return fmt.Sprintf("%s: requires %s %s but %s has no version", v.RequiredBy, name, v.Constraint, name)
+	}
+	return fmt.Sprintf("%s: requires %s %s but found %s", v.RequiredBy, name, v.Constraint, v.Version)
+}
+
+// String returns a description of the invalid manifest.
+func (i InvalidManifest) String() string {
+	return fmt.Sprintf("%s: invalid manifest: %v", i.Plugin, i.Err)
+}
+
+// String returns the cycle as an arrow-separated path.
+func (c PluginCycle) String() string {
+	return "plugin cycle detected: " + strings.Join(c, " -> ")
+}
+
+// PluginReport collects every problem found in the plugins of a Core rather than stopping at the first one,
+// together with the order the plugins are initialized in.
+type PluginReport struct {
+	Missing   []MissingPlugin
+	Conflicts []VersionConflict
+	Invalid   []InvalidManifest
+	Cycles    []PluginCycle
+	// Order lists the plugin names dependencies first; it is empty if the report is not valid.
+	Order []string
+}
+
+// Valid returns true if the report contains no errors.
+func (r *PluginReport) Valid() bool {
+	return len(r.Missing) == 0 && len(r.Conflicts) == 0 && len(r.Invalid) == 0 && len(r.Cycles) == 0
+}
+
+// Err returns all errors of the report joined together, or nil if the report is valid.
+func (r *PluginReport) Err() error {
+	var errs []error
+	for _, i := range r.Invalid {
+		errs = append(errs, errors.New(i.String()))
+	}
+	for _, m := range r.Missing {
+		errs = append(errs, errors.New(m.String()))
+	}
+	for _, c := range r.Conflicts {
+		errs = append(errs, errors.New(c.String()))
+	}
+	for _, c := range r.Cycles {
+		errs = append(errs, errors.New(c.String()))
+	}
+	return errors.Join(errs...)
+}
+
+// String returns a multi-line, human-readable summary of the report.
+func (r *PluginReport) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "plugin report: %d invalid manifests, %d missing, %d conflicts, %d cycles",
+		len(r.Invalid), len(r.Missing), len(r.Conflicts), len(r.Cycles))
+	if err := r.Err(); err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(sb, "\n  error: %s", line)
+		}
+	}
+	if len(r.Order) > 0 {
+		fmt.Fprintf(sb, "\n  order: %s", strings.Join(r.Order, ", "))
+	}
+	return sb.String()
+}
+
+// SetHostVersion sets the version of the host application, checked against the Host constraint of the
+// plugin manifests during Init.
+func (c *Core) SetHostVersion(version string) error {
+	if err := c.checkReadonlyMode(); err != nil {
+		return err
+	}
+	if _, err := util.ParseVersion(version); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.hostVersion = version
+	return nil
+}
+
+// HostVersion returns the version set with SetHostVersion, or an empty string.
+func (c *Core) HostVersion() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.hostVersion
+}
+
+// ValidatePlugins checks the manifests, parents and requirements of every registered plugin and returns a
+// report of all problems. If the report is valid, its Order lists the plugins with parents and requirements
+// first, in an order that is stable for the same set of plugins.
+func (c *Core) ValidatePlugins() *PluginReport {
+	report := &PluginReport{}
+	plugins := c.sortedPlugins()
+	versions := map[string]util.Version{}
+	for _, plugin := range plugins {
+		if plugin.manifest.Version == "" {
+			continue
+		}
+		v, err := util.ParseVersion(plugin.manifest.Version)
+		if err != nil {
+			report.Invalid = append(report.Invalid, InvalidManifest{Plugin: plugin, Err: err})
+			continue
+		}
+		versions[plugin.Name()] = v
+	}
+	host := c.HostVersion()
+	dag := util.NewDAG()
+	for _, plugin := range plugins {
+		var deps []string
+		if parent := plugin.Parent(); parent != "" && parent != MainNamespace {
+			deps = append(deps, parent)
+			if _, ok := c.plugins.Load(parent); !ok {
+				report.Missing = append(report.Missing, MissingPlugin{Name: parent, Parent: true, RequiredBy: plugin})
+			}
+		}
+		if constraint := plugin.manifest.Host; constraint != "" {
+			c.checkRequirement(report, plugin, "", host, constraint)
+		}
+		for _, name := range slices.Sorted(maps.Keys(plugin.manifest.Requires)) {
+			constraint := plugin.manifest.Requires[name]
+			deps = append(deps, name)
+			value, ok := c.plugins.Load(name)
+			if !ok {
+				report.Missing = append(report.Missing, MissingPlugin{Name: name, Constraint: constraint, RequiredBy: plugin})
+				continue
+			}
+			version := value.(*Plugin).manifest.Version
+			if _, ok := versions[name]; !ok && version != "" {
+				continue // reported as an invalid manifest
+			}
+			c.checkRequirement(report, plugin, name, version, constraint)
+		}
+		dag.AddNode(plugin.Name(), deps...)
+	}
+	for _, cycle := range dag.Cycles() {
+		report.Cycles = append(report.Cycles, cycle)
+	}
+	if !report.Valid() {
+		return report
+	}
+	// no cycles were found, so the graph can be sorted
+	report.Order, _ = dag.Sort()
+	return report
+}
+
+// checkRequirement adds a conflict to the report if the version of the plugin name, or of the host if name is
+// empty, is missing or does not satisfy the constraint, or an invalid manifest if the constraint cannot be
+// parsed. Unparsable versions are reported as invalid manifests of their own plugins.
+func (c *Core) checkRequirement(report *PluginReport, plugin *Plugin, name, version, constraint string) {
+	cons, err := util.ParseConstraint(constraint)
+	if err != nil {
+		report.Invalid = append(report.Invalid, InvalidManifest{Plugin: plugin, Err: err})
+		return
+	}
+	conflict := VersionConflict{Name: name, Version: version, Constraint: cons.String(), RequiredBy: plugin}
+	if version == "" {
+		report.Conflicts = append(report.Conflicts, conflict)
+		return
+	}
+	if v, err := util.ParseVersion(version); err == nil && !cons.Check(v) {
+		report.Conflicts = append(report.Conflicts, conflict)
+	}
+}
+
+// sortedPlugins returns the registered plugins sorted by name.
+func (c *Core) sortedPlugins() []*Plugin {
+	var plugins []*Plugin
+	c.plugins.Range(func(key any, value any) bool {
+		plugins = append(plugins, value.(*Plugin))
+		return true
+	})
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name() < plugins[j].Name() })
+	return plugins
+}
+
+// orderPlugins validates the plugins and returns them in initialization order, logging each plugin's
+// version and resolution chain.
+func (c *Core) orderPlugins() ([]*Plugin, error) {
+	report := c.ValidatePlugins()
+	if !report.Valid() {
+		return nil, errors.Join(ErrInvalidPlugins, report.Err())
+	}
+	plugins := make([]*Plugin, 0, len(report.Order))
+	for _, name := range report.Order {
+		value, _ := c.plugins.Load(name)
+		plugin := value.(*Plugin)
+		plugins = append(plugins, plugin)
+		util.Logger().Debug("plugin resolution chain", zap.String("plugin", name),
+			zap.String("version", plugin.manifest.Version), zap.Strings("chain", c.ResolutionChain(name)))
+	}
+	return plugins, nil
+}
@@ -0,0 +1,133 @@
+package business
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlugin_Manifest(t *testing.T) {
+	p := NewPlugin("acme",
+		WithManifest(Manifest{Version: "1.0.0", Requires: map[string]string{"retail": "^1.0"}}),
+		WithVersion("1.2.0"), WithDescription("acme tenant"), WithHostVersion(">=2.0"), WithRequire("pay", "~1.4"))
+	m := p.Manifest()
+	want := Manifest{Version: "1.2.0", Description: "acme tenant", Host: ">=2.0",
+		Requires: map[string]string{"retail": "^1.0", "pay": "~1.4"}}
+	if !reflect.DeepEqual(m, want) || p.Version() != "1.2.0" {
+		t.Fatalf("unexpected manifest %+v", m)
+	}
+	// 返回的是副本
+	m.Requires["other"] = "*"
+	if _, ok := p.Manifest().Requires["other"]; ok {
+		t.Fatalf("manifest requirements must be copied")
+	}
+}
+
+// 插件按依赖拓扑顺序初始化，而非注册顺序
+func TestCoreInit_PluginOrder(t *testing.T) {
+	c := newCore()
+	var order []string
+	add := func(name string, opts ...PluginOption) {
+		p := NewPlugin(name, opts...)
+		p.Init(func() error {
+			order = append(order, name)
+			return nil
+		})
+		RegisterPluginTo(c, p)
+	}
+	add("acme", WithParent("retail"), WithRequire("pay", "^1.2"))
+	add("pay", WithVersion("1.4.0"), WithRequire("base", ">=0.1"))
+	add("retail", WithVersion("2.0.0"))
+	add("base", WithVersion("0.3.1"))
+	add("audit")
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if len(order) != 5 {
+		t.Fatalf("expected 5 plugins initialized, got %v", order)
+	}
+	idx := map[string]int{}
+	for i, name := range order {
+		idx[name] = i
+	}
+	for _, dep := range [][2]string{{"base", "pay"}, {"pay", "acme"}, {"retail", "acme"}} {
+		if idx[dep[0]] > idx[dep[1]] {
+			t.Fatalf("expected %s before %s, got %v", dep[0], dep[1], order)
+		}
+	}
+	if report := c.ValidatePlugins(); !reflect.DeepEqual(report.Order, order) {
+		t.Fatalf("report order %v should match init order %v", report.Order, order)
+	}
+}
+
+func TestCoreInit_PluginReport(t *testing.T) {
+	c := newCore()
+	if err := c.SetHostVersion("1.5.0"); err != nil {
+		t.Fatalf("set host version failed: %v", err)
+	}
+	called := false
+	ok := NewPlugin("ok", WithVersion("1.0.0"))
+	ok.Init(func() error {
+		called = true
+		return nil
+	})
+	RegisterPluginTo(c, ok)
+	RegisterPluginTo(c, NewPlugin("old", WithVersion("1.1.0")))
+	RegisterPluginTo(c, NewPlugin("bad", WithVersion("one")))
+	RegisterPluginTo(c, NewPlugin("unversioned"))
+	RegisterPluginTo(c, NewPlugin("app", WithHostVersion("^2.0"),
+		WithRequire("old", "^1.2"), WithRequire("missing", "^1.0"), WithRequire("unversioned", "*"),
+		WithRequire("ok", ">>1")))
+
+	report := c.ValidatePlugins()
+	if report.Valid() || len(report.Order) != 0 {
+		t.Fatalf("report should be invalid: %s", report)
+	}
+	if len(report.Invalid) != 2 || len(report.Missing) != 1 || len(report.Conflicts) != 3 {
+		t.Fatalf("unexpected report: %s", report)
+	}
+	err := c.Init()
+	if !errors.Is(err, ErrInvalidPlugins) {
+		t.Fatalf("expected ErrInvalidPlugins, got %v", err)
+	}
+	for _, msg := range []string{
+		`<Plugin bad>: invalid manifest: invalid version "one"`,
+		`<Plugin app>: invalid manifest: invalid constraint ">>1"`,
+		"<Plugin app>: required plugin missing ^1.0 is not registered",
+		"<Plugin app>: requires host ^2.0 but found 1.5.0",
+		"<Plugin app>: requires plugin old ^1.2 but found 1.1.0",
+		"<Plugin app>: requires plugin unversioned * but plugin unversioned has no version",
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Fatalf("expected %q in error:\n%v", msg, err)
+		}
+	}
+	if called {
+		t.Fatalf("no plugin should be initialized when the report is invalid")
+	}
+}
+
+func TestCore_SetHostVersion(t *testing.T) {
+	c := newCore()
+	if err := c.SetHostVersion("1.x"); err == nil {
+		t.Fatalf("expected error for invalid host version")
+	}
+	RegisterPluginTo(c, NewPlugin("p", WithHostVersion(">=1.0")))
+	if err := c.Init(); err == nil || !strings.Contains(err.Error(), "host has no version") {
+		t.Fatalf("expected missing host version error, got %v", err)
+	}
+	if err := c.SetHostVersion("1.0.0"); err != ErrInReadonlyMode {
+		t.Fatalf("expected ErrInReadonlyMode, got %v", err)
+	}
+}
+
+func TestCoreInit_PluginDependencyCycle(t *testing.T) {
+	c := newCore()
+	RegisterPluginTo(c, NewPlugin("a", WithVersion("1.0.0"), WithRequire("b", "*")))
+	RegisterPluginTo(c, NewPlugin("b", WithVersion("1.0.0"), WithRequire("a", "*")))
+	err := c.Init()
+	if !errors.Is(err, ErrInvalidPlugins) || !strings.Contains(err.Error(), "plugin cycle detected: a -> b -> a") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
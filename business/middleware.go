@@ -0,0 +1,57 @@
+package business
+
+import (
+	"context"
+	"net/http"
+)
+
+// HTTPMiddleware returns a middleware installing the business Context resolved by the Core from the request
+// attributes returned by extract, so handlers can call Execute with the request context.
+func HTTPMiddleware(c *Core, extract func(r *http.Request) Attributes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := c.ResolveContext(r.Context(), extract(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// HeaderAttributes returns an attribute extractor reading each attribute from the request header it is mapped
+// to, such as {"tenant": "X-Tenant"}. Missing headers are left out of the attributes.
+func HeaderAttributes(headers map[string]string) func(r *http.Request) Attributes {
+	return func(r *http.Request) Attributes {
+		return MetadataAttributes(headers, r.Header.Get)
+	}
+}
+
+// MetadataAttributes reads each attribute through get from the metadata key it is mapped to, such as
+// {"tenant": "x-tenant"}. Keys get returns an empty value for are left out of the attributes.
+func MetadataAttributes(keys map[string]string, get func(key string) string) Attributes {
+	attrs := Attributes{}
+	for attr, key := range keys {
+		if v := get(key); v != "" {
+			attrs[attr] = v
+		}
+	}
+	return attrs
+}
+
+// MetadataContext returns the business Context resolved by the Core from the attributes read through get with
+// MetadataAttributes. It installs the business identity for transports other than net/http; a gRPC unary
+// interceptor passes the incoming metadata:
+//
+//	func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+//		md, _ := metadata.FromIncomingContext(ctx)
+//		return handler(business.MetadataContext(core, ctx, keys, func(key string) string {
+//			if v := md.Get(key); len(v) > 0 {
+//				return v[0]
+//			}
+//			return ""
+//		}), req)
+//	}
+//
+// A stream interceptor does the same with ss.Context() and hands the handler a grpc.ServerStream wrapper whose
+// Context method returns the result.
+func MetadataContext(c *Core, ctx context.Context, keys map[string]string, get func(key string) string) Context {
+	return c.ResolveContext(ctx, MetadataAttributes(keys, get))
+}
@@ -1,26 +1,51 @@
 package business
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"vortice/object"
 )
 
+// PluginOption is a function type for configuring a Plugin when it is created.
+type PluginOption func(p *Plugin)
+
+// WithParent declares the parent of the plugin: extensions the plugin does not implement are resolved in the
+// parent's namespace, then in the parent's parent and so on, before MainNamespace.
+func WithParent(parent string) PluginOption {
+	return func(p *Plugin) {
+		p.parent = parent
+	}
+}
+
 // Plugin represents a plugin with initialization functions, extensions, and abilities.
 type Plugin struct {
 	name       string
+	parent     string
 	inits      []func() error
+	starts     []func() error
+	stops      []func() error
+	destroys   []func() error
 	extensions map[string]*object.Definition
 	abilities  map[string]*object.Definition
+	rules      []Rule
+	manifest   Manifest
+	gate       *pluginGate
 }
 
-// NewPlugin creates a new Plugin instance with the specified name and associated Core.
-func NewPlugin(name string) *Plugin {
-	return &Plugin{
+// NewPlugin creates a new Plugin instance with the specified name, configured by the options.
+func NewPlugin(name string, opts ...PluginOption) *Plugin {
+	p := &Plugin{
 		name:       name,
 		inits:      []func() error{},
 		extensions: map[string]*object.Definition{},
 		abilities:  map[string]*object.Definition{},
+		gate:       newPluginGate(),
+	}
+	for _, option := range opts {
+		option(p)
 	}
+	return p
 }
 
 // Name returns the name of the plugin.
@@ -28,11 +53,64 @@ func (p *Plugin) Name() string {
 	return p.name
 }
 
+// Parent returns the name of the parent plugin declared with WithParent, or an empty string.
+func (p *Plugin) Parent() string {
+	return p.parent
+}
+
+// Manifest returns the manifest of the plugin.
+func (p *Plugin) Manifest() Manifest {
+	m := p.manifest
+	m.Requires = maps.Clone(p.manifest.Requires)
+	return m
+}
+
+// Version returns the version declared in the manifest of the plugin, or an empty string.
+func (p *Plugin) Version() string {
+	return p.manifest.Version
+}
+
 // Init appends initialization functions to the plugin, to be executed during the plugin's initialization.
 func (p *Plugin) Init(fn ...func() error) {
 	p.inits = append(p.inits, fn...)
 }
 
+// OnStart appends functions executed by Core.Start once the container is started. Plugins start parents and
+// required plugins first, and a plugin's functions run in the order they were added.
+func (p *Plugin) OnStart(fn ...func() error) {
+	p.starts = append(p.starts, fn...)
+}
+
+// OnStop appends functions executed by Core.Shutdown for a started plugin, before the container is shut down.
+// Plugins stop in the reverse order they started, and all functions run even if one fails.
+func (p *Plugin) OnStop(fn ...func() error) {
+	p.stops = append(p.stops, fn...)
+}
+
+// OnDestroy appends functions executed by Core.Shutdown for an initialized plugin, after the container is shut
+// down. Plugins are destroyed in the reverse order they were initialized, and all functions run even if one fails.
+func (p *Plugin) OnDestroy(fn ...func() error) {
+	p.destroys = append(p.destroys, fn...)
+}
+
+// Match routes the requests whose attributes include all of attrs to the plugin's namespace. Rules matching
+// more attributes come first in the resolved namespace chain, so a plugin for tenant=acme&channel=app is
+// searched before a plugin for tenant=acme. Rules are collected when the Core initializes.
+func (p *Plugin) Match(attrs Attributes) {
+	p.AddRule(Rule{Match: attrs})
+}
+
+// AddRule declares a routing rule to the plugin's namespace, overriding the namespace of the rule.
+func (p *Plugin) AddRule(rule Rule) {
+	rule.Namespace = p.name
+	p.rules = append(p.rules, rule)
+}
+
+// Rules returns the routing rules declared with Match and AddRule.
+func (p *Plugin) Rules() []Rule {
+	return append([]Rule{}, p.rules...)
+}
+
 // GetExtension retrieves the object definition for a given extension name, returning nil if not found.
 func (p *Plugin) GetExtension(name string) *object.Definition {
 	if def, ok := p.extensions[name]; ok {
@@ -64,3 +142,31 @@ func (p *Plugin) init() error {
 	}
 	return nil
 }
+
+// start executes the start functions of the plugin, stopping at the first error.
+func (p *Plugin) start() error {
+	for _, startFunc := range p.starts {
+		if err := startFunc(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stop executes all stop functions of the plugin, returning their errors joined together.
+func (p *Plugin) stop() error {
+	var errs []error
+	for _, stopFunc := range p.stops {
+		errs = append(errs, stopFunc())
+	}
+	return errors.Join(errs...)
+}
+
+// destroy executes all destroy functions of the plugin, returning their errors joined together.
+func (p *Plugin) destroy() error {
+	var errs []error
+	for _, destroyFunc := range p.destroys {
+		errs = append(errs, destroyFunc())
+	}
+	return errors.Join(errs...)
+}
@@ -182,3 +182,12 @@ func TestPlugin_Integration(t *testing.T) {
 		}
 	})
 }
+
+func TestPlugin_WithParent(t *testing.T) {
+	if p := NewPlugin("acme", WithParent("retail")); p.Parent() != "retail" {
+		t.Fatalf("expected parent retail, got %q", p.Parent())
+	}
+	if p := NewPlugin("retail"); p.Parent() != "" {
+		t.Fatalf("expected no parent, got %q", p.Parent())
+	}
+}
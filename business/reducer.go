@@ -0,0 +1,106 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"vortice/container"
+	"vortice/object"
+)
+
+// Reducer combines the results of the extensions run by ExecuteAll into a single output. Each result is passed
+// to the step function with the output so far; the step can stop the execution early, and an error stops it
+// and is returned by ExecuteAll.
+type Reducer[R, O any] struct {
+	init O
+	step func(acc O, r R) (O, bool, error)
+}
+
+// NewReducer creates a Reducer starting from init; step returns the new output, whether to stop, and an error.
+func NewReducer[R, O any](init O, step func(acc O, r R) (O, bool, error)) Reducer[R, O] {
+	return Reducer[R, O]{init: init, step: step}
+}
+
+// Reduce creates a Reducer folding every result into the output with fn, starting from init.
+func Reduce[R, O any](init O, fn func(acc O, r R) O) Reducer[R, O] {
+	return NewReducer(init, func(acc O, r R) (O, bool, error) {
+		return fn(acc, r), false, nil
+	})
+}
+
+// FirstNonNil creates a Reducer returning the first result that is neither nil nor the zero value and
+// skipping the remaining extensions.
+func FirstNonNil[R any]() Reducer[R, R] {
+	var zero R
+	return NewReducer(zero, func(acc R, r R) (R, bool, error) {
+		if rv := reflect.ValueOf(r); !rv.IsValid() || rv.IsZero() {
+			return acc, false, nil
+		}
+		return r, true, nil
+	})
+}
+
+// CollectAll creates a Reducer returning the results of every extension in execution order.
+func CollectAll[R any]() Reducer[R, []R] {
+	return Reduce(nil, func(acc []R, r R) []R {
+		return append(acc, r)
+	})
+}
+
+// AllMustPass creates a Reducer for extensions returning an error, such as validators: the first error
+// stops the execution and is returned by ExecuteAll.
+func AllMustPass() Reducer[error, error] {
+	return NewReducer(nil, func(_ error, r error) (error, bool, error) {
+		return r, r != nil, r
+	})
+}
+
+// ExecuteAll calls fn with every extension E of the default Core applicable to the namespace of ctx and
+// combines the results with the reducer. See ExecuteAllWith.
+/*
+	type OrderValidator interface{ Validate(order *Order) error }
+	_, err := business.ExecuteAll(ctx, func(v OrderValidator) error { return v.Validate(order) }, business.AllMustPass())
+*/
+func ExecuteAll[E, R, O any](ctx context.Context, fn func(E) R, reducer Reducer[R, O]) (O, error) {
+	return ExecuteAllWith(DefaultCore(), ctx, fn, reducer)
+}
+
+// ExecuteAllWith calls fn with every extension E of the given Core registered in the resolution chain of the
+// namespace of ctx and combines the results with the reducer. Extensions run by descending priority, declared
+// with WithPriority; equal priorities run in resolution chain order, then in registration order. A plugin
+// disabled with DisablePlugin waits for the call to finish.
+func ExecuteAllWith[E, R, O any](c *Core, ctx context.Context, fn func(E) R, reducer Reducer[R, O]) (O, error) {
+	acc := reducer.init
+	name := object.GenerateDefinitionName(reflect.TypeOf((*E)(nil)).Elem())
+	chain := c.ResolutionChainOf(ctx)
+	var objs []container.Object
+	for _, ns := range chain {
+		leave, ok := c.enterNamespace(ns)
+		if !ok {
+			continue
+		}
+		defer leave()
+		found, err := c.extensionObjects(ctx, ns, name)
+		if err != nil {
+			return acc, err
+		}
+		objs = append(objs, found...)
+	}
+	if len(objs) == 0 {
+		return acc, fmt.Errorf("%w: %s in namespaces %s", ErrExtensionNotFound, name, strings.Join(chain, ", "))
+	}
+	sortByPriority(objs)
+	for _, obj := range objs {
+		ext, err := extensionOf[E](obj)
+		if err != nil {
+			return acc, err
+		}
+		var stop bool
+		if acc, stop, err = reducer.step(acc, fn(ext)); err != nil || stop {
+			return acc, err
+		}
+	}
+	return acc, nil
+}
@@ -0,0 +1,139 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"vortice/object"
+)
+
+type adjuster interface{ Adjust(price int) int }
+type validator interface{ Validate(v int) error }
+
+type addAdjuster struct{ n int }
+type limitValidator struct {
+	name  string
+	limit int
+}
+
+func (a addAdjuster) Adjust(price int) int { return price + a.n }
+func (l limitValidator) Validate(v int) error {
+	if v > l.limit {
+		return errors.New(l.name + " rejected")
+	}
+	return nil
+}
+
+// main 注册两个带优先级的扩展，vip 插件再贡献一个
+func newReducerCore(t *testing.T) *Core {
+	t.Helper()
+	c := newCore()
+	RegisterExtTo0(c, func() adjuster { return addAdjuster{n: 1} }, WithPriority(1))
+	RegisterExtTo0(c, func() adjuster { return addAdjuster{n: 10} }, WithPriority(5))
+	RegisterExtTo0(c, func() validator { return limitValidator{name: "main", limit: 100} }, WithPriority(0))
+	vip := NewPlugin("vip")
+	vip.Init(func() error {
+		RegisterExtTo0(c, func() adjuster { return addAdjuster{n: 100} }, WithPriority(3))
+		RegisterExtTo0(c, func() validator { return limitValidator{name: "vip", limit: 10} }, WithPriority(9))
+		return nil
+	})
+	RegisterPluginTo(c, vip)
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	return c
+}
+
+func TestExecuteAllWith_CollectAllByPriority(t *testing.T) {
+	c := newReducerCore(t)
+	adjust := func(a adjuster) int { return a.Adjust(0) }
+	got, err := ExecuteAllWith(c, WithContext(context.Background(), "vip"), adjust, CollectAll[int]())
+	if err != nil || !reflect.DeepEqual(got, []int{10, 100, 1}) {
+		t.Fatalf("expected results ordered by priority, got %v %v", got, err)
+	}
+	// main 命名空间不包含插件的扩展
+	got, err = ExecuteAllWith(c, context.Background(), adjust, CollectAll[int]())
+	if err != nil || !reflect.DeepEqual(got, []int{10, 1}) {
+		t.Fatalf("main should only run its own extensions, got %v %v", got, err)
+	}
+}
+
+func TestExecuteAllWith_Reduce(t *testing.T) {
+	c := newReducerCore(t)
+	sum := Reduce(0, func(acc, r int) int { return acc + r })
+	got, err := ExecuteAllWith(c, WithContext(context.Background(), "vip"), func(a adjuster) int { return a.Adjust(0) }, sum)
+	if err != nil || got != 111 {
+		t.Fatalf("expected 111, got %d %v", got, err)
+	}
+}
+
+func TestExecuteAllWith_FirstNonNil(t *testing.T) {
+	c := newReducerCore(t)
+	calls := 0
+	fn := func(a adjuster) *int {
+		calls++
+		if v := a.Adjust(0); v != 10 {
+			return &v
+		}
+		return nil
+	}
+	got, err := ExecuteAllWith(c, WithContext(context.Background(), "vip"), fn, FirstNonNil[*int]())
+	if err != nil || got == nil || *got != 100 || calls != 2 {
+		t.Fatalf("expected the first non-nil result 100 after 2 calls, got %v %d %v", got, calls, err)
+	}
+}
+
+func TestExecuteAllWith_AllMustPass(t *testing.T) {
+	c := newReducerCore(t)
+	validate := func(v int) func(validator) error {
+		return func(val validator) error { return val.Validate(v) }
+	}
+	if _, err := ExecuteAllWith(c, WithContext(context.Background(), "vip"), validate(5), AllMustPass()); err != nil {
+		t.Fatalf("all validators should pass, got %v", err)
+	}
+	_, err := ExecuteAllWith(c, WithContext(context.Background(), "vip"), validate(50), AllMustPass())
+	if err == nil || err.Error() != "vip rejected" {
+		t.Fatalf("the higher-priority vip validator should reject first, got %v", err)
+	}
+	if _, err := ExecuteAllWith(c, context.Background(), validate(50), AllMustPass()); err != nil {
+		t.Fatalf("main validators should pass, got %v", err)
+	}
+}
+
+// Execute 在同一命名空间内选择优先级最高的扩展
+func TestExecuteWith_PrefersHighestPriority(t *testing.T) {
+	c := newReducerCore(t)
+	got, err := ExecuteWith(c, context.Background(), func(a adjuster) int { return a.Adjust(0) })
+	if err != nil || got != 10 {
+		t.Fatalf("expected the priority 5 extension, got %d %v", got, err)
+	}
+}
+
+func TestExecuteAllWith_NotFound(t *testing.T) {
+	c := newCore()
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	_, err := ExecuteAllWith(c, context.Background(), func(a adjuster) int { return 0 }, CollectAll[int]())
+	if !errors.Is(err, ErrExtensionNotFound) {
+		t.Fatalf("expected ErrExtensionNotFound, got %v", err)
+	}
+}
+
+// 未声明优先级的重复 main 扩展仍然被拒绝
+func TestRegisterExtension_DuplicateWithoutPriority(t *testing.T) {
+	c := newCore()
+	if _, err := c.RegisterExtension(func() adjuster { return addAdjuster{} }, object.NewProperty()); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	_, err := c.RegisterExtension(func() adjuster { return addAdjuster{} }, object.NewProperty())
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected a duplicate error, got %v", err)
+	}
+	if n := len(c.Container().GetDefinitions()); n != 1 {
+		t.Fatalf("rejected extension must not stay registered, got %d definitions", n)
+	}
+}
@@ -0,0 +1,407 @@
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vortice/business"
+	"vortice/object"
+	"vortice/util"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrUnavailable is the error returned by Invoke when the plugin process cannot be reached, for instance
+	// because it crashed.
+	ErrUnavailable = errors.New("remote plugin unavailable")
+	// ErrProtocolMismatch is the error returned by Launch when the plugin speaks another ProtocolVersion.
+	ErrProtocolMismatch = errors.New("remote plugin protocol version mismatch")
+
+	proxies = &proxyRegistry{mutex: &sync.RWMutex{}, factories: map[string]proxyFactory{}}
+)
+
+type (
+	// Invoker calls the methods of one extension of a plugin process. args are encoded as JSON, and the results
+	// of the method, without a trailing error, are decoded into results, which must be pointers.
+	Invoker interface {
+		Invoke(method string, args []any, results ...any) error
+	}
+	// MethodError is the error returned by Invoke when the remote method itself returned an error.
+	MethodError struct {
+		// Message is the message of the error returned by the method.
+		Message string
+	}
+	// CrashedEvent is published on the container's EventBus when a plugin process exits unexpectedly or
+	// cannot be reached anymore, just before its namespace is disabled.
+	CrashedEvent struct {
+		// Name is the name of the plugin.
+		Name string
+		// Err describes the crash.
+		Err error
+	}
+)
+
+// Error returns the message of the remote error.
+func (e *MethodError) Error() string {
+	return e.Message
+}
+
+// proxyFactory creates the proxy of an extension interface.
+type proxyFactory struct {
+	typ reflect.Type
+	new func(inv Invoker) any
+}
+
+// proxyRegistry holds the proxy factories registered with RegisterProxy, keyed by extension name.
+type proxyRegistry struct {
+	mutex     *sync.RWMutex
+	factories map[string]proxyFactory
+}
+
+// RegisterProxy registers the factory of the proxy of the extension interface E, used for every plugin process
+// advertising E. vortice-gen -proxy generates proxies and their registration; a proxy implements E by
+// calling the Invoker, typically with one line per method:
+/*
+	type calculatorProxy struct{ remote.Invoker }
+
+	func (p calculatorProxy) Price(order *Order) (int, error) {
+		var price int
+		err := p.Invoke("Price", []any{order}, &price)
+		return price, err
+	}
+
+	remote.RegisterProxy(func(inv remote.Invoker) pricing.Calculator { return calculatorProxy{inv} })
+*/
+func RegisterProxy[E any](newProxy func(inv Invoker) E) {
+	typ := reflect.TypeOf((*E)(nil)).Elem()
+	proxies.mutex.Lock()
+	defer proxies.mutex.Unlock()
+	proxies.factories[object.GenerateDefinitionName(typ)] = proxyFactory{
+		typ: typ,
+		new: func(inv Invoker) any { return newProxy(inv) },
+	}
+}
+
+// lookup returns the proxy factory of the extension name.
+func (r *proxyRegistry) lookup(name string) (proxyFactory, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	f, ok := r.factories[name]
+	return f, ok
+}
+
+// Option is a function type for configuring Launch.
+type Option func(o *options)
+
+// options holds the configuration of Launch.
+type options struct {
+	args         []string
+	env          []string
+	startTimeout time.Duration
+	callTimeout  time.Duration
+}
+
+// WithArgs sets the command line arguments of the plugin process.
+func WithArgs(args ...string) Option {
+	return func(o *options) {
+		o.args = args
+	}
+}
+
+// WithEnv adds environment variables, in the form key=value, to the environment of the plugin process,
+// which otherwise inherits the host's.
+func WithEnv(env ...string) Option {
+	return func(o *options) {
+		o.env = append(o.env, env...)
+	}
+}
+
+// WithStartTimeout sets how long Launch waits for the plugin process to connect and describe itself; the
+// default is 10 seconds.
+func WithStartTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.startTimeout = timeout
+	}
+}
+
+// WithCallTimeout limits how long Invoke waits for a method of the plugin process; the default is no limit.
+// A timed out call does not disable the plugin.
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.callTimeout = timeout
+	}
+}
+
+// Process is a plugin process launched by the host.
+type Process struct {
+	core        *business.Core
+	desc        Description
+	cmd         *exec.Cmd
+	client      *rpc.Client
+	callTimeout time.Duration
+	closing     *atomic.Bool
+	registered  *atomic.Bool
+	crashed     *sync.Once
+	done        chan struct{}
+	err         error
+}
+
+// Launch starts the plugin binary at path, connects to it over a unix socket and registers a business.Plugin
+// named after the remote plugin with the Core, so it must be called before Init. When the Core initializes,
+// the plugin registers a proxy, see RegisterProxy, for every extension the process advertises; Init fails,
+// naming them, if any extension has no proxy. The process is closed when the Core shuts down.
+//
+// If the process exits unexpectedly or cannot be reached, a CrashedEvent is published and the plugin is
+// disabled with DisablePlugin, so requests fall back to its parent and main namespaces.
+func Launch(c *business.Core, path string, opts ...Option) (*Process, error) {
+	o := &options{startTimeout: 10 * time.Second}
+	for _, option := range opts {
+		option(o)
+	}
+	dir, err := os.MkdirTemp("", "vortice-plugin-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	listener, err := net.Listen("unix", filepath.Join(dir, "plugin.sock"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = listener.Close() }()
+
+	cmd := exec.Command(path, o.args...)
+	cmd.Env = append(append(os.Environ(), o.env...), SocketEnv+"="+listener.Addr().String())
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("launch plugin %s failed: %w", path, err)
+	}
+	p := &Process{
+		core:        c,
+		cmd:         cmd,
+		callTimeout: o.callTimeout,
+		closing:     &atomic.Bool{},
+		registered:  &atomic.Bool{},
+		crashed:     &sync.Once{},
+		done:        make(chan struct{}),
+	}
+	go p.wait(listener)
+	if err := p.connect(listener, o.startTimeout); err != nil {
+		p.abort()
+		return nil, fmt.Errorf("launch plugin %s failed: %w", path, err)
+	}
+	plugin := business.NewPlugin(p.desc.Name,
+		business.WithVersion(p.desc.Version), business.WithDescription(p.desc.Description))
+	plugin.Init(p.registerProxies)
+	plugin.OnDestroy(p.Close)
+	if err := c.RegisterPlugin(plugin); err != nil {
+		p.abort()
+		return nil, fmt.Errorf("launch plugin %s failed: %w", path, err)
+	}
+	p.registered.Store(true)
+	return p, nil
+}
+
+// Name returns the name of the plugin.
+func (p *Process) Name() string {
+	return p.desc.Name
+}
+
+// Description returns what the plugin process advertised when it was launched.
+func (p *Process) Description() Description {
+	desc := p.desc
+	desc.Extensions = append([]string{}, p.desc.Extensions...)
+	return desc
+}
+
+// Done returns a channel closed once the plugin process has exited.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the error the plugin process exited with, once Done is closed.
+func (p *Process) Err() error {
+	<-p.done
+	return p.err
+}
+
+// Close closes the connection to the plugin process, which makes a process served by Server.Run exit, and
+// kills the process if it has not exited within 5 seconds. Closing a closed Process does nothing.
+func (p *Process) Close() error {
+	if !p.closing.CompareAndSwap(false, true) {
+		return nil
+	}
+	if p.client != nil {
+		_ = p.client.Close()
+	}
+	select {
+	case <-p.done:
+	case <-time.After(5 * time.Second):
+		_ = p.cmd.Process.Kill()
+		<-p.done
+	}
+	return nil
+}
+
+// abort kills the process of a failed Launch and waits for it to exit.
+func (p *Process) abort() {
+	p.closing.Store(true)
+	if p.client != nil {
+		_ = p.client.Close()
+	}
+	_ = p.cmd.Process.Kill()
+	<-p.done
+}
+
+// connect accepts the connection of the plugin process and asks it to describe itself.
+func (p *Process) connect(listener net.Listener, timeout time.Duration) error {
+	if err := listener.(*net.UnixListener).SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	conn, err := listener.Accept()
+	if err != nil {
+		return fmt.Errorf("plugin process did not connect: %w", err)
+	}
+	p.client = rpc.NewClient(conn)
+	call := p.client.Go(serviceName+".Describe", struct{}{}, &p.desc, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+	case <-time.After(timeout):
+		return fmt.Errorf("plugin process did not describe itself within %s", timeout)
+	}
+	switch {
+	case call.Error != nil:
+		return call.Error
+	case p.desc.Protocol != ProtocolVersion:
+		return fmt.Errorf("%w: plugin %q, host %q", ErrProtocolMismatch, p.desc.Protocol, ProtocolVersion)
+	case p.desc.Name == "":
+		return errors.New("plugin process has no name")
+	}
+	return nil
+}
+
+// wait waits for the plugin process to exit, treating any exit not caused by Close as a crash. It closes the
+// listener so that a process exiting before it connects does not keep Launch waiting.
+func (p *Process) wait(listener net.Listener) {
+	p.err = p.cmd.Wait()
+	_ = listener.Close()
+	close(p.done)
+	if !p.closing.Load() {
+		p.crash(fmt.Errorf("plugin process exited: %v", p.err))
+	}
+}
+
+// crash publishes a CrashedEvent, kills the process and disables the plugin, once. It runs asynchronously
+// because it may be called by an extension call in flight, which DisablePlugin waits for.
+func (p *Process) crash(err error) {
+	if !p.registered.Load() {
+		return // Launch fails and closes the process
+	}
+	p.crashed.Do(func() {
+		go func() {
+			util.Logger().Error("remote plugin crashed", zap.String("plugin", p.desc.Name), zap.Error(err))
+			p.core.Container().EventBus().Publish(CrashedEvent{Name: p.desc.Name, Err: err})
+			_ = p.cmd.Process.Kill()
+			if err := p.core.DisablePlugin(p.desc.Name); err != nil {
+				util.Logger().Error("disable crashed plugin failed", zap.String("plugin", p.desc.Name), zap.Error(err))
+			}
+		}()
+	})
+}
+
+// registerProxies registers the proxies of the advertised extensions in the namespace of the plugin, or
+// closes the process and returns an error listing the extensions without a proxy; it runs as the init
+// function of the plugin, whose OnDestroy functions do not run if it fails.
+func (p *Process) registerProxies() error {
+	var missing []string
+	for _, name := range p.desc.Extensions {
+		if _, ok := proxies.lookup(name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		_ = p.Close()
+		return fmt.Errorf("no proxy registered for extensions %s advertised by plugin %s, see RegisterProxy",
+			strings.Join(missing, ", "), p.desc.Name)
+	}
+	for _, name := range p.desc.Extensions {
+		factory, _ := proxies.lookup(name)
+		proxy := factory.new(&invoker{process: p, extension: name})
+		if _, err := p.core.RegisterExtensionInstance(factory.typ, proxy, object.NewProperty()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// call invokes a method of the plugin process, treating transport errors as a crash.
+func (p *Process) call(req Request) (Response, error) {
+	var resp Response
+	call := p.client.Go(serviceName+".Invoke", req, &resp, make(chan *rpc.Call, 1))
+	if p.callTimeout > 0 {
+		select {
+		case <-call.Done:
+		case <-time.After(p.callTimeout):
+			return resp, fmt.Errorf("call %s.%s of plugin %s timed out after %s",
+				req.Extension, req.Method, p.desc.Name, p.callTimeout)
+		}
+	} else {
+		<-call.Done
+	}
+	var serverErr rpc.ServerError
+	switch {
+	case call.Error == nil:
+		return resp, nil
+	case errors.As(call.Error, &serverErr):
+		return resp, call.Error
+	}
+	if !p.closing.Load() {
+		p.crash(call.Error)
+	}
+	return resp, fmt.Errorf("%w: %s: %v", ErrUnavailable, p.desc.Name, call.Error)
+}
+
+// invoker is the Invoker of one extension of a plugin process.
+type invoker struct {
+	process   *Process
+	extension string
+}
+
+// Invoke calls the method of the extension in the plugin process.
+func (i *invoker) Invoke(method string, args []any, results ...any) error {
+	req := Request{Extension: i.extension, Method: method, Args: make([]json.RawMessage, len(args))}
+	for n, arg := range args {
+		raw, err := json.Marshal(arg)
+		if err != nil {
+			return fmt.Errorf("encode argument %d of %s.%s: %w", n, i.extension, method, err)
+		}
+		req.Args[n] = raw
+	}
+	resp, err := i.process.call(req)
+	if err != nil {
+		return err
+	}
+	if len(resp.Results) != len(results) {
+		return fmt.Errorf("method %s.%s returned %d results, expected %d", i.extension, method, len(resp.Results), len(results))
+	}
+	for n, raw := range resp.Results {
+		if err := json.Unmarshal(raw, results[n]); err != nil {
+			return fmt.Errorf("decode result %d of %s.%s: %w", n, i.extension, method, err)
+		}
+	}
+	if resp.Error != "" {
+		return &MethodError{Message: resp.Error}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Package remote runs business plugins out of process.
+//
+// A plugin binary creates a Server, implements extension interfaces with Implement and calls Run. The host
+// starts the binary with Launch, which connects to it over a unix socket using net/rpc, registers a
+// business.Plugin named after the remote plugin, and registers a proxy for every extension the plugin
+// advertises; the proxies are registered with RegisterProxy, usually by code generated with
+// vortice-gen -proxy, and Init fails if one is missing. If the plugin process crashes, its namespace is
+// disabled and requests fall back to the parent and main namespaces.
+package remote
+
+import "encoding/json"
+
+// ProtocolVersion is the version of the protocol between the host and plugin processes. Launch refuses
+// plugins speaking another version.
+const ProtocolVersion = "1"
+
+const (
+	// SocketEnv is the environment variable holding the path of the unix socket a plugin process connects to.
+	SocketEnv = "VORTICE_PLUGIN_SOCKET"
+	// serviceName is the name the plugin process serves its rpc methods under.
+	serviceName = "VorticePlugin"
+)
+
+type (
+	// Description is what a plugin process advertises about itself when the host connects.
+	Description struct {
+		// Protocol is the ProtocolVersion the plugin was built with.
+		Protocol string
+		// Name is the name of the plugin, used as its namespace.
+		Name string
+		// Version is the version of the plugin.
+		Version string
+		// Description is a human-readable description of the plugin.
+		Description string
+		// Extensions are the definition names of the extension interfaces the plugin implements.
+		Extensions []string
+	}
+	// Request invokes a method of an extension implemented by the plugin process.
+	Request struct {
+		// Extension is the definition name of the extension interface.
+		Extension string
+		// Method is the name of the method.
+		Method string
+		// Args are the JSON encoded arguments of the method.
+		Args []json.RawMessage
+	}
+	// Response is the result of a Request.
+	Response struct {
+		// Results are the JSON encoded results of the method, without a trailing error.
+		Results []json.RawMessage
+		// Error is the message of the error returned by the method, if any.
+		Error string
+	}
+)
@@ -0,0 +1,241 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"vortice/business"
+	"vortice/container"
+)
+
+// 测试二进制通过该环境变量以插件进程模式重新启动自身
+const testModeEnv = "VORTICE_REMOTE_TEST_MODE"
+
+type greeter interface {
+	Greet(name string) (string, error)
+}
+
+type crasher interface {
+	Crash() error
+}
+
+// waver 没有注册代理
+type waver interface {
+	Wave() error
+}
+
+type acmeWaver struct{}
+
+func (acmeWaver) Wave() error { return nil }
+
+type acmeGreeter struct{}
+
+func (acmeGreeter) Greet(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("name required")
+	}
+	return "acme greets " + name, nil
+}
+
+type exitCrasher struct{}
+
+func (exitCrasher) Crash() error {
+	os.Exit(3)
+	return nil
+}
+
+type mainGreeter struct{}
+
+func (mainGreeter) Greet(name string) (string, error) { return "hello " + name, nil }
+
+type greeterProxy struct{ Invoker }
+
+func (p greeterProxy) Greet(name string) (string, error) {
+	var out string
+	err := p.Invoke("Greet", []any{name}, &out)
+	return out, err
+}
+
+type crasherProxy struct{ Invoker }
+
+func (p crasherProxy) Crash() error { return p.Invoke("Crash", nil) }
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(testModeEnv); mode != "" {
+		runTestPlugin(mode)
+		os.Exit(0)
+	}
+	RegisterProxy(func(inv Invoker) greeter { return greeterProxy{inv} })
+	RegisterProxy(func(inv Invoker) crasher { return crasherProxy{inv} })
+	os.Exit(m.Run())
+}
+
+// runTestPlugin 以插件进程身份运行
+func runTestPlugin(mode string) {
+	srv := NewServer("acme", WithVersion("1.2.0"), WithDescription("acme tenant"))
+	Implement[greeter](srv, acmeGreeter{})
+	Implement[crasher](srv, exitCrasher{})
+	switch mode {
+	case "protocol":
+		srv.desc.Protocol = "0"
+	case "unproxied":
+		Implement[waver](srv, acmeWaver{})
+	case "silent":
+		time.Sleep(10 * time.Second)
+		return
+	}
+	if err := srv.Run(); err != nil {
+		os.Exit(2)
+	}
+}
+
+// 启动插件进程并初始化 Core
+func launch(t *testing.T, mode string, opts ...Option) (*business.Core, *Process) {
+	t.Helper()
+	c := business.NewCore(container.NewCore(context.Background()))
+	business.RegisterExtTo0(c, func() greeter { return mainGreeter{} })
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Launch(c, exe, append(opts, WithEnv(testModeEnv+"="+mode))...)
+	if err != nil {
+		t.Fatalf("launch failed: %v", err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	return c, p
+}
+
+func greet(c *business.Core, name string) (string, error) {
+	g, err := business.GetExtension[greeter](c, business.WithContext(context.Background(), "acme"))
+	if err != nil {
+		return "", err
+	}
+	return g.Greet(name)
+}
+
+func TestLaunch_Execute(t *testing.T) {
+	c, p := launch(t, "acme")
+	desc := p.Description()
+	if p.Name() != "acme" || desc.Version != "1.2.0" || len(desc.Extensions) != 2 {
+		t.Fatalf("unexpected description %+v", desc)
+	}
+	if got, err := greet(c, "bob"); err != nil || got != "acme greets bob" {
+		t.Fatalf("expected the remote greeter, got %q %v", got, err)
+	}
+	// 远端方法返回的错误
+	var methodErr *MethodError
+	if _, err := greet(c, ""); !errors.As(err, &methodErr) || methodErr.Message != "name required" {
+		t.Fatalf("expected MethodError, got %v", err)
+	}
+	c.Shutdown()
+	select {
+	case <-p.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("plugin process should exit on shutdown")
+	}
+	if !c.PluginEnabled("acme") {
+		t.Fatalf("closing must not be treated as a crash")
+	}
+}
+
+// 插件进程崩溃只禁用其命名空间，宿主回退到 main
+func TestLaunch_Crash(t *testing.T) {
+	c, p := launch(t, "acme")
+	defer c.Shutdown()
+	crashed := make(chan CrashedEvent, 1)
+	container.Subscribe(c.Container().EventBus(), func(e CrashedEvent) { crashed <- e })
+
+	ctx := business.WithContext(context.Background(), "acme")
+	_, err := business.ExecuteWith(c, ctx, func(cr crasher) error { return cr.Crash() })
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	select {
+	case e := <-crashed:
+		if e.Name != "acme" || e.Err == nil {
+			t.Fatalf("unexpected crash event %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected a CrashedEvent")
+	}
+	<-p.Done()
+	deadline := time.Now().Add(5 * time.Second)
+	for c.PluginEnabled("acme") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if c.PluginEnabled("acme") {
+		t.Fatalf("crashed plugin should be disabled")
+	}
+	if got, err := greet(c, "bob"); err != nil || got != "hello bob" {
+		t.Fatalf("expected fallback to main, got %q %v", got, err)
+	}
+}
+
+func TestLaunch_Errors(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := business.NewCore(container.NewCore(context.Background()))
+	if _, err := Launch(c, exe, WithEnv(testModeEnv+"=protocol")); !errors.Is(err, ErrProtocolMismatch) {
+		t.Fatalf("expected ErrProtocolMismatch, got %v", err)
+	}
+	start := time.Now()
+	_, err = Launch(c, exe, WithEnv(testModeEnv+"=silent"), WithStartTimeout(200*time.Millisecond))
+	if err == nil || !strings.Contains(err.Error(), "did not connect") || time.Since(start) > 5*time.Second {
+		t.Fatalf("expected a start timeout, got %v", err)
+	}
+	if _, err := Launch(c, exe+"-missing"); err == nil {
+		t.Fatalf("expected error for a missing binary")
+	}
+	if c.PluginEnabled("acme") {
+		t.Fatalf("failed launches must not register plugins")
+	}
+}
+
+// 插件声明了没有代理的扩展时 Init 失败
+func TestLaunch_MissingProxy(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := business.NewCore(container.NewCore(context.Background()))
+	defer c.Shutdown()
+	p, err := Launch(c, exe, WithEnv(testModeEnv+"=unproxied"))
+	if err != nil {
+		t.Fatalf("launch failed: %v", err)
+	}
+	err = c.Init()
+	if err == nil || !strings.Contains(err.Error(), "no proxy registered for extensions vortice/business/remote.waver") {
+		t.Fatalf("expected a missing proxy error, got %v", err)
+	}
+	select {
+	case <-p.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("plugin process should exit when its proxies cannot be registered")
+	}
+}
+
+func TestServer_Invoke(t *testing.T) {
+	srv := NewServer("acme")
+	Implement[greeter](srv, acmeGreeter{})
+	cases := map[string]Request{
+		"is not implemented":   {Extension: "unknown", Method: "Greet"},
+		"has no method":        {Extension: srv.description().Extensions[0], Method: "Wave"},
+		"takes 1 arguments":    {Extension: srv.description().Extensions[0], Method: "Greet"},
+		"decode argument 0 of": {Extension: srv.description().Extensions[0], Method: "Greet", Args: []json.RawMessage{json.RawMessage("1")}},
+	}
+	for want, req := range cases {
+		if _, err := srv.invoke(req); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
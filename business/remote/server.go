@@ -0,0 +1,179 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+
+	"vortice/object"
+	"vortice/util"
+
+	"go.uber.org/zap"
+)
+
+// errorType is the reflect.Type of the error interface.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ServerOption is a function type for configuring a Server when it is created.
+type ServerOption func(s *Server)
+
+// WithVersion sets the version the Server advertises.
+func WithVersion(version string) ServerOption {
+	return func(s *Server) {
+		s.desc.Version = version
+	}
+}
+
+// WithDescription sets the description the Server advertises.
+func WithDescription(description string) ServerOption {
+	return func(s *Server) {
+		s.desc.Description = description
+	}
+}
+
+// Server serves the extensions of a plugin process to the host.
+/*
+	func main() {
+		srv := remote.NewServer("acme", remote.WithVersion("1.0.0"))
+		remote.Implement[pricing.Calculator](srv, acmeCalculator{})
+		if err := srv.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}
+*/
+type Server struct {
+	mutex *sync.RWMutex
+	desc  Description
+	impls map[string]reflect.Value
+}
+
+// NewServer creates a Server for the plugin with the given name, configured by the options.
+func NewServer(name string, opts ...ServerOption) *Server {
+	s := &Server{
+		mutex: &sync.RWMutex{},
+		desc:  Description{Protocol: ProtocolVersion, Name: name},
+		impls: map[string]reflect.Value{},
+	}
+	for _, option := range opts {
+		option(s)
+	}
+	return s
+}
+
+// Implement serves impl as the extension E, an interface type shared with the host. Method arguments and
+// results are transferred as JSON, and a trailing error result is returned to the host as a MethodError.
+func Implement[E any](s *Server, impl E) {
+	name := object.GenerateDefinitionName(reflect.TypeOf((*E)(nil)).Elem())
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.impls[name] = reflect.ValueOf(&impl).Elem()
+}
+
+// Run connects to the socket named by SocketEnv and serves the host until it closes the connection.
+func (s *Server) Run() error {
+	socket := os.Getenv(SocketEnv)
+	if socket == "" {
+		return fmt.Errorf("%s is not set, the plugin must be started by the host", SocketEnv)
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return err
+	}
+	s.Serve(conn)
+	return nil
+}
+
+// Serve serves the host on conn until the connection is closed.
+func (s *Server) Serve(conn io.ReadWriteCloser) {
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &service{server: s}); err != nil {
+		util.Logger().Panic("Serve", zap.Error(err))
+	}
+	server.ServeConn(conn)
+}
+
+// description returns the Description of the Server, extensions sorted by name.
+func (s *Server) description() Description {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	desc := s.desc
+	desc.Extensions = make([]string, 0, len(s.impls))
+	for name := range s.impls {
+		desc.Extensions = append(desc.Extensions, name)
+	}
+	sort.Strings(desc.Extensions)
+	return desc
+}
+
+// invoke calls the method of the request on the extension implementation.
+func (s *Server) invoke(req Request) (resp Response, err error) {
+	s.mutex.RLock()
+	impl, ok := s.impls[req.Extension]
+	s.mutex.RUnlock()
+	if !ok {
+		return resp, fmt.Errorf("extension %s is not implemented", req.Extension)
+	}
+	method := impl.MethodByName(req.Method)
+	if !method.IsValid() {
+		return resp, fmt.Errorf("extension %s has no method %s", req.Extension, req.Method)
+	}
+	mt := method.Type()
+	if mt.IsVariadic() || mt.NumIn() != len(req.Args) {
+		return resp, fmt.Errorf("method %s.%s takes %d arguments, got %d", req.Extension, req.Method, mt.NumIn(), len(req.Args))
+	}
+	args := make([]reflect.Value, len(req.Args))
+	for i, raw := range req.Args {
+		arg := reflect.New(mt.In(i))
+		if err := json.Unmarshal(raw, arg.Interface()); err != nil {
+			return resp, fmt.Errorf("decode argument %d of %s.%s: %w", i, req.Extension, req.Method, err)
+		}
+		args[i] = arg.Elem()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("method %s.%s panicked: %v", req.Extension, req.Method, r)
+		}
+	}()
+	out := method.Call(args)
+	if n := len(out); n > 0 && mt.Out(n-1) == errorType {
+		if e, _ := out[n-1].Interface().(error); e != nil {
+			resp.Error = e.Error()
+		}
+		out = out[:n-1]
+	}
+	for i, result := range out {
+		raw, err := json.Marshal(result.Interface())
+		if err != nil {
+			return resp, fmt.Errorf("encode result %d of %s.%s: %w", i, req.Extension, req.Method, err)
+		}
+		resp.Results = append(resp.Results, raw)
+	}
+	return resp, nil
+}
+
+// service exposes a Server as net/rpc methods.
+type service struct {
+	server *Server
+}
+
+// Describe returns the Description of the plugin.
+func (s *service) Describe(_ struct{}, desc *Description) error {
+	*desc = s.server.description()
+	return nil
+}
+
+// Invoke calls a method of an extension implemented by the plugin.
+func (s *service) Invoke(req Request, resp *Response) error {
+	result, err := s.server.invoke(req)
+	if err != nil {
+		return err
+	}
+	*resp = result
+	return nil
+}
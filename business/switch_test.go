@@ -0,0 +1,125 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"vortice/container"
+)
+
+func TestDisablePlugin_FallsBack(t *testing.T) {
+	c := newExecuteCore(t)
+	var events []any
+	container.Subscribe(c.Container().EventBus(), func(e PluginDisabledEvent) { events = append(events, e) })
+	container.Subscribe(c.Container().EventBus(), func(e PluginEnabledEvent) { events = append(events, e) })
+	ctx := WithContext(context.Background(), "vip")
+	greet := func(g greeter) string { return g.Greet("bob") }
+
+	if err := c.DisablePlugin("vip"); err != nil {
+		t.Fatalf("disable failed: %v", err)
+	}
+	if c.PluginEnabled("vip") {
+		t.Fatalf("vip should be disabled")
+	}
+	if chain := c.ResolutionChainOf(ctx); !reflect.DeepEqual(chain, []string{MainNamespace}) {
+		t.Fatalf("disabled namespace should leave the chain, got %v", chain)
+	}
+	if got, err := ExecuteWith(c, ctx, greet); err != nil || got != "hello bob" {
+		t.Fatalf("disabled vip should fall back to main, got %q %v", got, err)
+	}
+	// 重复禁用不再发布事件
+	if err := c.DisablePlugin("vip"); err != nil {
+		t.Fatalf("second disable failed: %v", err)
+	}
+	if err := c.EnablePlugin("vip"); err != nil {
+		t.Fatalf("enable failed: %v", err)
+	}
+	if got, err := ExecuteWith(c, ctx, greet); err != nil || got != "welcome back bob" {
+		t.Fatalf("enabled vip should be used again, got %q %v", got, err)
+	}
+	want := []any{PluginDisabledEvent{Name: "vip"}, PluginEnabledEvent{Name: "vip"}}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("expected events %v, got %v", want, events)
+	}
+	if err := c.DisablePlugin("unknown"); !errors.Is(err, ErrPluginNotFound) {
+		t.Fatalf("expected ErrPluginNotFound, got %v", err)
+	}
+}
+
+// 禁用的父插件被跳过，但继续沿其父链解析
+func TestDisablePlugin_Parent(t *testing.T) {
+	c := newCore()
+	RegisterPluginTo(c, NewPlugin("retail", WithParent("base")))
+	RegisterPluginTo(c, NewPlugin("base"))
+	RegisterPluginTo(c, NewPlugin("acme", WithParent("retail")))
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if err := c.DisablePlugin("retail"); err != nil {
+		t.Fatalf("disable failed: %v", err)
+	}
+	if chain := c.ResolutionChain("acme"); !reflect.DeepEqual(chain, []string{"acme", "base", MainNamespace}) {
+		t.Fatalf("unexpected chain %v", chain)
+	}
+}
+
+// 禁用等待进行中的调用结束，新调用立即回退
+func TestDisablePlugin_WaitsForInFlight(t *testing.T) {
+	c := newExecuteCore(t)
+	ctx := WithContext(context.Background(), "vip")
+	entered, release := make(chan struct{}), make(chan struct{})
+	go func() {
+		_, _ = ExecuteWith(c, ctx, func(g greeter) string {
+			close(entered)
+			<-release
+			return g.Greet("bob")
+		})
+	}()
+	<-entered
+	disabled := make(chan error)
+	go func() { disabled <- c.DisablePlugin("vip") }()
+
+	deadline := time.Now().Add(time.Second)
+	for c.PluginEnabled("vip") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got, err := ExecuteWith(c, ctx, func(g greeter) string { return g.Greet("amy") }); err != nil || got != "hello amy" {
+		t.Fatalf("new calls should fall back while draining, got %q %v", got, err)
+	}
+	select {
+	case err := <-disabled:
+		t.Fatalf("disable should wait for the in-flight call, returned %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case err := <-disabled:
+		if err != nil {
+			t.Fatalf("disable failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("disable should return once the in-flight call finished")
+	}
+}
+
+func TestExecuteAllWith_SkipsDisabled(t *testing.T) {
+	c := newExecuteCore(t)
+	ctx := WithContext(context.Background(), "vip")
+	collect := func() []string {
+		out, err := ExecuteAllWith(c, ctx, func(g greeter) string { return g.Greet("bob") }, CollectAll[string]())
+		if err != nil {
+			t.Fatalf("execute all failed: %v", err)
+		}
+		return out
+	}
+	if got := collect(); len(got) != 2 {
+		t.Fatalf("expected vip and main greeters, got %v", got)
+	}
+	_ = c.DisablePlugin("vip")
+	if got := collect(); !reflect.DeepEqual(got, []string{"hello bob"}) {
+		t.Fatalf("expected only the main greeter, got %v", got)
+	}
+}
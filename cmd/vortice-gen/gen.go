@@ -0,0 +1,654 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"vortice/util"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	// vorticePkgPath is the import path of the package declaring Register0..6.
+	vorticePkgPath = "vortice"
+	// businessPkgPath is the import path of the package declaring RegisterExt0..6.
+	businessPkgPath = "vortice/business"
+)
+
+var (
+	// registerFuncs matches the names of the registration functions recognised per package.
+	registerFuncs = map[string]*regexp.Regexp{
+		vorticePkgPath:  regexp.MustCompile(`^Register[0-6]$`),
+		businessPkgPath: regexp.MustCompile(`^RegisterExt[0-6]$`),
+	}
+	// scopeOptions maps the options of vorticePkgPath that set the scope to whether it is Prototype.
+	scopeOptions = map[string]bool{
+		"WithPrototype":   true,
+		"WithSingleton":   false,
+		"WithAutoStartup": false,
+	}
+	// conditionOptions are the options of vorticePkgPath that make a registration one of several alternatives
+	// selected at Init.
+	conditionOptions = map[string]bool{
+		"WithProfile":   true,
+		"WithCondition": true,
+		"WithOnMissing": true,
+		"WithOnPresent": true,
+	}
+)
+
+// registration is a single factory registration found in the scanned packages.
+type registration struct {
+	pos     token.Position
+	factory *types.Func
+	name    string
+	result  types.Type
+	params  []types.Type
+	deps    []string
+	field   string
+	// prototype is true if the registration sets the Prototype scope; the generated code then constructs
+	// the component on every use instead of once.
+	prototype bool
+	// conditional is true if the registration has a profile or condition, so that it is one of several
+	// alternatives for its name selected at Init.
+	conditional bool
+	// override is true if the registration replaces the other registrations of its name.
+	override bool
+}
+
+// generator scans packages for registrations and emits the wiring file.
+type generator struct {
+	regs   []*registration
+	byName map[string]*registration
+	// namespaces holds the registrations made by the init functions of plugins, keyed by plugin and name.
+	// They override main registrations in the plugin's namespace at runtime and are not wired.
+	namespaces map[string]map[string]*registration
+	errs       []error
+	warnings   []string
+}
+
+// newGenerator creates an empty generator.
+func newGenerator() *generator {
+	return &generator{byName: map[string]*registration{}, namespaces: map[string]map[string]*registration{}}
+}
+
+// load loads the packages matching the patterns relative to dir.
+func load(dir string, patterns ...string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e)
+		}
+	})
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages matched %v", patterns)
+	}
+	return pkgs, nil
+}
+
+// scan records every recognised registration call in the packages.
+func (g *generator) scan(pkgs []*packages.Package) {
+	inits := findPluginInits(pkgs)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				ns := ""
+				if fd, ok := decl.(*ast.FuncDecl); ok {
+					ns = inits.funcs[pkg.TypesInfo.Defs[fd.Name]]
+				}
+				ast.Inspect(decl, func(n ast.Node) bool {
+					if call, ok := n.(*ast.CallExpr); ok {
+						g.scanCall(pkg, call, inits.namespace(ns, call.Pos()))
+					}
+					return true
+				})
+			}
+		}
+	}
+}
+
+// pluginInits locates the init functions passed to business.Plugin.Init, whose registrations belong to the
+// namespace of the plugin.
+type pluginInits struct {
+	// funcs maps named init functions to their plugin.
+	funcs map[types.Object]string
+	// lits holds the function literals passed as init functions.
+	lits []pluginInitLit
+}
+
+// pluginInitLit is a function literal passed to Plugin.Init.
+type pluginInitLit struct {
+	pos, end token.Pos
+	plugin   string
+}
+
+// findPluginInits finds the init functions passed to Plugin.Init in the packages. A plugin is named by the
+// constant passed to business.NewPlugin when that can be traced, otherwise by the receiver expression.
+func findPluginInits(pkgs []*packages.Package) *pluginInits {
+	inits := &pluginInits{funcs: map[types.Object]string{}}
+	names := map[types.Object]string{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+					for i, lhs := range assign.Lhs {
+						if id, ok := lhs.(*ast.Ident); ok {
+							if name, ok := pluginName(pkg.TypesInfo, assign.Rhs[i]); ok {
+								names[pkg.TypesInfo.ObjectOf(id)] = name
+							}
+						}
+					}
+				}
+				if spec, ok := n.(*ast.ValueSpec); ok && len(spec.Names) == len(spec.Values) {
+					for i, id := range spec.Names {
+						if name, ok := pluginName(pkg.TypesInfo, spec.Values[i]); ok {
+							names[pkg.TypesInfo.Defs[id]] = name
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || !isPluginInit(typeutil.Callee(pkg.TypesInfo, call)) {
+					return true
+				}
+				sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				plugin := types.ExprString(sel.X)
+				if id, ok := ast.Unparen(sel.X).(*ast.Ident); ok && names[pkg.TypesInfo.ObjectOf(id)] != "" {
+					plugin = names[pkg.TypesInfo.ObjectOf(id)]
+				} else if name, ok := pluginName(pkg.TypesInfo, sel.X); ok {
+					plugin = name
+				}
+				for _, arg := range call.Args {
+					switch a := ast.Unparen(arg).(type) {
+					case *ast.FuncLit:
+						inits.lits = append(inits.lits, pluginInitLit{pos: a.Pos(), end: a.End(), plugin: plugin})
+					default:
+						if fn := factoryFunc(pkg.TypesInfo, a); fn != nil {
+							inits.funcs[fn] = plugin
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+	return inits
+}
+
+// namespace returns the plugin whose init function contains pos, or ns, the plugin of the enclosing
+// function declaration.
+func (inits *pluginInits) namespace(ns string, pos token.Pos) string {
+	for _, lit := range inits.lits {
+		if lit.pos <= pos && pos < lit.end {
+			return lit.plugin
+		}
+	}
+	return ns
+}
+
+// isPluginInit reports whether fn is the Init method of business.Plugin.
+func isPluginInit(fn types.Object) bool {
+	f, ok := fn.(*types.Func)
+	return ok && pkgPath(f) == businessPkgPath && f.Name() == "Init" && f.Type().(*types.Signature).Recv() != nil
+}
+
+// pluginName returns the name passed as a constant to business.NewPlugin by expr.
+func pluginName(info *types.Info, expr ast.Expr) (string, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok || pkgPath(fn) != businessPkgPath || fn.Name() != "NewPlugin" {
+		return "", false
+	}
+	tv := info.Types[call.Args[0]]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// scanCall records the call if it is a registration with a named factory function, made in the namespace
+// of the plugin ns or in the main namespace if ns is empty.
+func (g *generator) scanCall(pkg *packages.Package, call *ast.CallExpr, ns string) {
+	callee := typeutil.Callee(pkg.TypesInfo, call)
+	if callee == nil || callee.Pkg() == nil || len(call.Args) == 0 {
+		return
+	}
+	re, ok := registerFuncs[callee.Pkg().Path()]
+	if !ok || !re.MatchString(callee.Name()) {
+		return
+	}
+	pos := pkg.Fset.Position(call.Pos())
+	factory := factoryFunc(pkg.TypesInfo, call.Args[0])
+	if factory == nil {
+		g.warnf("%s: skipping %s: factory is not a named function", pos, callee.Name())
+		return
+	}
+	sig := factory.Type().(*types.Signature)
+	if sig.Results().Len() != 1 {
+		g.warnf("%s: skipping %s: factory must have exactly one return value", pos, factory.FullName())
+		return
+	}
+	reg := &registration{pos: pos, factory: factory, result: sig.Results().At(0).Type()}
+	if reg.name = definitionName(reg.result); reg.name == "" {
+		g.warnf("%s: skipping %s: invalid output type %s", pos, factory.FullName(), reg.result)
+		return
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		typ := sig.Params().At(i).Type()
+		if isIn(typ) {
+			g.warnf("%s: skipping %s: In struct argument %s needs values resolved at Init", pos, factory.FullName(), typ)
+			return
+		}
+		if isProvider(typ) {
+			g.warnf("%s: skipping %s: provider argument %s is resolved by the container on every call",
+				pos, factory.FullName(), typ)
+			return
+		}
+		name := definitionName(typ)
+		if name == "" {
+			g.warnf("%s: skipping %s: invalid argument type %s", pos, factory.FullName(), typ)
+			return
+		}
+		reg.params = append(reg.params, typ)
+		reg.deps = append(reg.deps, name)
+	}
+	g.options(pkg, call, reg)
+	if ns != "" {
+		if g.namespaces[ns] == nil {
+			g.namespaces[ns] = map[string]*registration{}
+		}
+		g.add(g.namespaces[ns], reg, "plugin "+ns)
+		return
+	}
+	if g.add(g.byName, reg, "the main namespace") {
+		g.regs = slices.DeleteFunc(g.regs, func(r *registration) bool { return r.name == reg.name })
+		g.regs = append(g.regs, reg)
+	}
+}
+
+// add records reg in the registrations of a namespace, returning true if it provides its name there. Of
+// several registrations of a name, an override wins; alternatives selected at Init by a profile or
+// condition keep the first, with a warning; any other duplicate is an error.
+func (g *generator) add(regs map[string]*registration, reg *registration, namespace string) bool {
+	prev, ok := regs[reg.name]
+	switch {
+	case !ok, reg.override && !prev.override:
+	case prev.override && !reg.override:
+		return false
+	case reg.conditional || prev.conditional:
+		g.warnf("%s: %s has alternatives in %s selected at Init, also registered by %s at %s; wiring the first",
+			reg.pos, reg.name, namespace, prev.factory.FullName(), prev.pos)
+		return false
+	default:
+		g.errs = append(g.errs, fmt.Errorf("%s: %s is provided in %s by both %s and %s at %s",
+			reg.pos, reg.name, namespace, reg.factory.FullName(), prev.factory.FullName(), prev.pos))
+		return false
+	}
+	regs[reg.name] = reg
+	return true
+}
+
+// options records the effect of the options of the registration call on reg: the scope, the last scope
+// option winning, whether it is conditional and whether it overrides. Options whose effect cannot be
+// determined statically are assumed to set none of them.
+func (g *generator) options(pkg *packages.Package, call *ast.CallExpr, reg *registration) {
+	for i, arg := range call.Args[1:] {
+		pos := pkg.Fset.Position(arg.Pos())
+		if call.Ellipsis.IsValid() && i == len(call.Args)-2 {
+			g.warnf("%s: cannot determine the effect of %s...; assuming an unconditional Singleton", pos, types.ExprString(arg))
+			continue
+		}
+		var callee *types.Func
+		if opt := optionCall(pkg.TypesInfo, arg); opt != nil {
+			callee, _ = typeutil.Callee(pkg.TypesInfo, opt).(*types.Func)
+		}
+		// options of vortice and vortice/business are known; any other function may set anything
+		if _, known := registerFuncs[pkgPath(callee)]; !known {
+			g.warnf("%s: cannot determine the effect of %s; assuming an unconditional Singleton", pos, types.ExprString(arg))
+			continue
+		}
+		if callee.Pkg().Path() != vorticePkgPath {
+			continue
+		}
+		if p, ok := scopeOptions[callee.Name()]; ok {
+			reg.prototype = p
+		}
+		reg.conditional = reg.conditional || conditionOptions[callee.Name()]
+		reg.override = reg.override || callee.Name() == "WithOverride"
+	}
+}
+
+// pkgPath returns the import path of the package declaring fn, or "".
+func pkgPath(fn *types.Func) string {
+	if fn == nil || fn.Pkg() == nil {
+		return ""
+	}
+	return fn.Pkg().Path()
+}
+
+// optionCall returns the function call producing an option argument, looking through parentheses and
+// conversions such as business.Option(vortice.WithPrototype()), or nil.
+func optionCall(info *types.Info, expr ast.Expr) *ast.CallExpr {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	if tv, ok := info.Types[call.Fun]; ok && tv.IsType() {
+		if len(call.Args) != 1 {
+			return nil
+		}
+		return optionCall(info, call.Args[0])
+	}
+	return call
+}
+
+// order returns the registrations dependencies first, or an error listing every duplicate registration,
+// every missing dependency and every cycle.
+func (g *generator) order() ([]*registration, error) {
+	dag := util.NewDAG()
+	errs := append([]error{}, g.errs...)
+	for _, reg := range g.regs {
+		dag.AddNode(reg.name, reg.deps...)
+		for _, dep := range reg.deps {
+			if _, ok := g.byName[dep]; !ok {
+				errs = append(errs, fmt.Errorf("%s: definition not found: %s (required by %s)",
+					reg.pos, dep, reg.factory.FullName()))
+			}
+		}
+	}
+	for _, cycle := range dag.Cycles() {
+		errs = append(errs, fmt.Errorf("cycle detected: %s", strings.Join(cycle, " -> ")))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	sorted, err := dag.Sort()
+	if err != nil {
+		return nil, err
+	}
+	ordered := make([]*registration, 0, len(sorted))
+	for _, name := range sorted {
+		ordered = append(ordered, g.byName[name])
+	}
+	return ordered, nil
+}
+
+// generate renders the wiring file for the output package. Singleton components become fields of the
+// Wiring struct, constructed once by NewWiring; Prototype components get a New method constructing them
+// on every call.
+func (g *generator) generate(out *types.Package) ([]byte, error) {
+	ordered, err := g.order()
+	if err != nil {
+		return nil, err
+	}
+	imports := newImportSet(out)
+	fields := map[string]bool{}
+	for _, reg := range ordered {
+		if reg.factory.Pkg() != out && !reg.factory.Exported() {
+			return nil, fmt.Errorf("%s: factory %s is unexported and cannot be called from package %s",
+				reg.pos, reg.factory.FullName(), out.Path())
+		}
+		if reg.prototype {
+			reg.field = uniqueField(fields, "New", reg.result)
+		} else {
+			reg.field = uniqueField(fields, "", reg.result)
+		}
+	}
+
+	body := &bytes.Buffer{}
+	fmt.Fprintf(body, "// Wiring holds every singleton component registered with vortice, constructed in dependency order.\n")
+	fmt.Fprintf(body, "type Wiring struct {\n")
+	for _, reg := range ordered {
+		if !reg.prototype {
+			fmt.Fprintf(body, "\t%s %s\n", reg.field, types.TypeString(reg.result, imports.qualify))
+		}
+	}
+	fmt.Fprintf(body, "}\n\n")
+	fmt.Fprintf(body, "// NewWiring constructs every singleton component by calling its factory directly, dependencies first.\n")
+	fmt.Fprintf(body, "func NewWiring() *Wiring {\n\tw := &Wiring{}\n")
+	temps := 0
+	for _, reg := range ordered {
+		if !reg.prototype {
+			call := g.call(body, imports, out, reg, &temps)
+			fmt.Fprintf(body, "\tw.%s = %s // %s:%d\n", reg.field, call, filepath.Base(reg.pos.Filename), reg.pos.Line)
+		}
+	}
+	fmt.Fprintf(body, "\treturn w\n}\n")
+	for _, reg := range ordered {
+		if !reg.prototype {
+			continue
+		}
+		result := types.TypeString(reg.result, imports.qualify)
+		fmt.Fprintf(body, "\n// %s constructs a new %s on every call, as its registration has the Prototype scope.\n",
+			reg.field, result)
+		fmt.Fprintf(body, "func (w *Wiring) %s() %s {\n", reg.field, result)
+		temps = 0
+		call := g.call(body, imports, out, reg, &temps)
+		fmt.Fprintf(body, "\treturn %s // %s:%d\n}\n", call, filepath.Base(reg.pos.Filename), reg.pos.Line)
+	}
+
+	src := &bytes.Buffer{}
+	fmt.Fprintf(src, "// Code generated by vortice-gen. DO NOT EDIT.\n\npackage %s\n\n", out.Name())
+	imports.write(src)
+	src.Write(body.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// call returns the expression calling the factory of reg. Prototype dependencies whose address is taken are
+// constructed into temporary variables first, declared in body and numbered from temps.
+func (g *generator) call(body *bytes.Buffer, imports *importSet, out *types.Package, reg *registration, temps *int) string {
+	args := make([]string, 0, len(reg.deps))
+	for i, dep := range reg.deps {
+		provider := g.byName[dep]
+		value := "w." + provider.field
+		if provider.prototype {
+			value += "()"
+			if needsAddress(provider.result, reg.params[i]) {
+				*temps++
+				tmp := fmt.Sprintf("p%d", *temps)
+				fmt.Fprintf(body, "\t%s := %s\n", tmp, value)
+				value = tmp
+			}
+		}
+		args = append(args, argument(value, provider.result, reg.params[i]))
+	}
+	fn := reg.factory.Name()
+	if reg.factory.Pkg() != out {
+		fn = imports.qualify(reg.factory.Pkg()) + "." + fn
+	}
+	return fmt.Sprintf("%s(%s)", fn, strings.Join(args, ", "))
+}
+
+// warnf records a non-fatal diagnostic.
+func (g *generator) warnf(format string, args ...any) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+// factoryFunc resolves the factory argument to a package-level function, or nil.
+func factoryFunc(info *types.Info, expr ast.Expr) *types.Func {
+	var ident *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return nil
+	}
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Type().(*types.Signature).Recv() != nil {
+		return nil
+	}
+	return fn
+}
+
+// definitionName mirrors object.GenerateDefinitionName for a static type, returning "" for
+// types the runtime registry would reject.
+func definitionName(typ types.Type) string {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+		if _, ok := typ.Underlying().(*types.Struct); !ok {
+			return ""
+		}
+	}
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	name := named.Obj().Name()
+	switch named.Underlying().(type) {
+	case *types.Struct:
+		name = "*" + name
+	case *types.Interface:
+	default:
+		return ""
+	}
+	return named.Obj().Pkg().Path() + "." + name
+}
+
+// argument returns the expression passing value, of the provider's result type, as a parameter of type param.
+func argument(value string, result, param types.Type) string {
+	switch {
+	case types.AssignableTo(result, param):
+		return value
+	case types.Identical(types.NewPointer(param), result):
+		return "*" + value
+	default:
+		return "&" + value
+	}
+}
+
+// needsAddress reports whether passing a value of type result as a parameter of type param takes its address.
+func needsAddress(result, param types.Type) bool {
+	return !types.AssignableTo(result, param) && !types.Identical(types.NewPointer(param), result)
+}
+
+// uniqueField derives an exported field or method name from the prefix and the type name, adding a suffix
+// on collision.
+func uniqueField(used map[string]bool, prefix string, typ types.Type) string {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	base := "Component"
+	if named, ok := typ.(*types.Named); ok {
+		r := []rune(named.Obj().Name())
+		r[0] = unicode.ToUpper(r[0])
+		base = string(r)
+	}
+	base = prefix + base
+	name := base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	used[name] = true
+	return name
+}
+
+// importSet collects the imports required by the generated file.
+type importSet struct {
+	out     *types.Package
+	aliases map[string]string
+	paths   []string
+	used    map[string]bool
+}
+
+// newImportSet creates an importSet for a file in package out.
+func newImportSet(out *types.Package) *importSet {
+	return &importSet{out: out, aliases: map[string]string{}, used: map[string]bool{}}
+}
+
+// qualify returns the name under which pkg is referenced, recording the import. It is a types.Qualifier.
+func (s *importSet) qualify(pkg *types.Package) string {
+	if pkg == s.out || pkg.Path() == s.out.Path() {
+		return ""
+	}
+	if alias, ok := s.aliases[pkg.Path()]; ok {
+		return alias
+	}
+	alias := pkg.Name()
+	for i := 2; s.used[alias] || alias == s.out.Name(); i++ {
+		alias = fmt.Sprintf("%s%d", pkg.Name(), i)
+	}
+	s.used[alias] = true
+	s.aliases[pkg.Path()] = alias
+	s.paths = append(s.paths, pkg.Path())
+	return alias
+}
+
+// write renders the import block.
+func (s *importSet) write(buf *bytes.Buffer) {
+	if len(s.paths) == 0 {
+		return
+	}
+	buf.WriteString("import (\n")
+	for _, path := range s.paths {
+		alias := s.aliases[path]
+		if alias == path[strings.LastIndex(path, "/")+1:] {
+			fmt.Fprintf(buf, "\t%q\n", path)
+		} else {
+			fmt.Fprintf(buf, "\t%s %q\n", alias, path)
+		}
+	}
+	buf.WriteString(")\n\n")
+}
+
+// isProvider reports whether typ is a function taking no arguments and returning a dependency, such as
+// object.Provider, which the runtime injects instead of the dependency itself.
+func isProvider(typ types.Type) bool {
+	sig, ok := typ.Underlying().(*types.Signature)
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 1 && definitionName(sig.Results().At(0).Type()) != ""
+}
+
+// isIn reports whether typ is a struct embedding object.In, whose fields the runtime injects one by one.
+func isIn(typ types.Type) bool {
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		named, ok := types.Unalias(field.Type()).(*types.Named)
+		if field.Embedded() && ok && named.Obj().Name() == "In" && named.Obj().Pkg() != nil &&
+			named.Obj().Pkg().Path() == "vortice/object" {
+			return true
+		}
+	}
+	return false
+}
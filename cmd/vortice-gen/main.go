@@ -0,0 +1,91 @@
+// Command vortice-gen generates compile-time wiring for components registered with vortice.
+//
+// It scans the given packages for calls to vortice.Register0..6 and business.RegisterExt0..6
+// whose factory is a named function, orders the factories by their dependencies and writes a
+// Wiring struct whose constructor calls every factory directly, without reflection. Components
+// registered with vortice.WithPrototype get a New method constructing them on every use instead
+// of a field. Missing and cyclic dependencies, and types registered more than once, fail
+// generation instead of surfacing at Init; alternatives selected at Init by a profile or condition
+// only warn and the first is wired, and WithOverride wins over the other registrations. Extensions
+// registered by the init functions of a plugin belong to its namespace and are not wired. The
+// generated file belongs to the first package matched; the runtime registry is unaffected.
+//
+//	//go:generate go run vortice/cmd/vortice-gen -o wiring_gen.go . ./service/...
+//
+// With -proxy, it instead generates the proxies the host needs to call extensions of out-of-process
+// plugins: for every listed interface, given as Name for a type of the first package or as
+// importpath.Name, a type implementing it through a remote.Invoker, registered with
+// remote.RegisterProxy by an init function of the first package.
+//
+//	//go:generate go run vortice/cmd/vortice-gen -proxy Calculator,vortice/example/tax.Rates -o proxy_gen.go .
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "vortice-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the command line, generates the wiring file and writes it to -o or stdout.
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("vortice-gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("o", "", "output file (default stdout)")
+	dir := fs.String("C", "", "directory to resolve package patterns in (default current directory)")
+	proxy := fs.String("proxy", "", "comma-separated extension interfaces to generate remote plugin proxies for, instead of the wiring")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	pkgs, err := load(*dir, patterns...)
+	if err != nil {
+		return err
+	}
+	var src []byte
+	if *proxy != "" {
+		src, err = proxies(pkgs, strings.Split(*proxy, ","))
+	} else {
+		src, err = wiring(pkgs, stderr)
+	}
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o644)
+}
+
+// wiring generates the wiring file of the registrations found in the packages, printing warnings to stderr.
+func wiring(pkgs []*packages.Package, stderr io.Writer) ([]byte, error) {
+	g := newGenerator()
+	g.scan(pkgs)
+	for _, warn := range g.warnings {
+		fmt.Fprintln(stderr, "warning:", warn)
+	}
+	return g.generate(pkgs[0].Types)
+}
+
+// proxies generates the proxy file of the named extension interfaces.
+func proxies(pkgs []*packages.Package, names []string) ([]byte, error) {
+	ifaces, err := lookupInterfaces(pkgs, names)
+	if err != nil {
+		return nil, err
+	}
+	return generateProxies(pkgs[0].Types, ifaces)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ---------- 生成成功：依赖顺序、指针/值转换、跨包调用 ----------
+func TestRun_Generate(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := run([]string{"./testdata/app"}, stdout, stderr); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	src := stdout.String()
+	if !strings.HasPrefix(src, "// Code generated by vortice-gen. DO NOT EDIT.") {
+		t.Fatalf("missing generated header:\n%s", src)
+	}
+	for _, want := range []string{
+		"package app",
+		`"vortice/cmd/vortice-gen/testdata/app/store"`,
+		"w.Config = newConfig()",
+		"w.Store = store.NewMemStore()",
+		"w.Service = newService(&w.Config, w.Store)",
+		"w.Greeter = newGreeter(w.Service)",
+		"w.Handler = newHandler(w.NewRequest())",
+		"func (w *Wiring) NewRequest() *Request {",
+		"p1 := w.NewClock()",
+		"return newRequest(&p1, w.Service)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %q:\n%s", want, src)
+		}
+	}
+	order := []string{"w.Config =", "w.Store =", "w.Service =", "w.Greeter ="}
+	for i := 1; i < len(order); i++ {
+		if strings.Index(src, order[i-1]) > strings.Index(src, order[i]) {
+			t.Errorf("%s should be constructed before %s", order[i-1], order[i])
+		}
+	}
+	// Prototype 组件不作为字段保存
+	if strings.Contains(src, "\tRequest *Request") || strings.Contains(src, "\tClock Clock") {
+		t.Errorf("prototype components should not be fields of Wiring:\n%s", src)
+	}
+	if !strings.Contains(stderr.String(), "factory is not a named function") {
+		t.Errorf("expected warning for closure factory, got %q", stderr.String())
+	}
+
+	// 生成的文件应能与包一起通过类型检查
+	typeCheck(t, "testdata/app", "wiring_gen.go", stdout.Bytes())
+}
+
+// typeCheck loads the package in dir together with the generated file.
+func typeCheck(t *testing.T, dir, name string, src []byte) {
+	t.Helper()
+	dir, _ = filepath.Abs(dir)
+	cfg := &packages.Config{
+		Mode:    packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir:     dir,
+		Overlay: map[string][]byte{filepath.Join(dir, name): src},
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatalf("generated file does not compile:\n%s", src)
+	}
+}
+
+// ---------- 生成失败：循环依赖 ----------
+func TestRun_Cycle(t *testing.T) {
+	err := run([]string{"./testdata/cycle"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "cycle detected") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+// ---------- 生成失败：同一类型被注册多次 ----------
+func TestRun_Duplicate(t *testing.T) {
+	err := run([]string{"./testdata/duplicate"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "duplicate.*A is provided in the main namespace by both") {
+		t.Fatalf("expected duplicate registration error, got %v", err)
+	}
+}
+
+// ---------- 插件 Init 中注册的扩展属于插件命名空间，不与 main 冲突 ----------
+func TestRun_PluginNamespace(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := run([]string{"./testdata/namespace"}, stdout, stderr); err != nil {
+		t.Fatalf("plugin overrides should not fail generation: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "w.Greeter = newGreeter()") || strings.Contains(stdout.String(), "Acme") {
+		t.Fatalf("only the main registration should be wired:\n%s", stdout.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("unexpected warnings: %s", stderr.String())
+	}
+}
+
+// ---------- 按 profile 或条件选择的备选注册只给出警告 ----------
+func TestRun_ConditionalAlternatives(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := run([]string{"./testdata/conditional"}, stdout, stderr); err != nil {
+		t.Fatalf("conditional alternatives should not fail generation: %v", err)
+	}
+	for _, want := range []string{"conditional.*DB has alternatives", "conditional.*Cache has alternatives"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("expected warning %q, got %q", want, stderr.String())
+		}
+	}
+	if !strings.Contains(stdout.String(), "w.DB = newDevDB()") || !strings.Contains(stdout.String(), "w.Repo = newRepo(w.DB)") {
+		t.Fatalf("the first alternative should be wired:\n%s", stdout.String())
+	}
+}
+
+// ---------- 生成失败：缺失依赖全部报告 ----------
+func TestRun_Missing(t *testing.T) {
+	err := run([]string{"./testdata/missing"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil {
+		t.Fatalf("expected missing dependency error")
+	}
+	for _, name := range []string{"missing.*B", "missing.*C"} {
+		if !strings.Contains(err.Error(), "definition not found: vortice/cmd/vortice-gen/testdata/"+name) {
+			t.Errorf("error should report %s: %v", name, err)
+		}
+	}
+}
+
+// ---------- In 结构体参数的工厂被跳过并给出警告 ----------
+func TestRun_InStructSkipped(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := run([]string{"./testdata/values"}, stdout, stderr); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "In struct argument") {
+		t.Fatalf("expected a warning for the In struct factory, got %q", stderr.String())
+	}
+	if strings.Contains(stdout.String(), "NewServer") || !strings.Contains(stdout.String(), "NewDep()") {
+		t.Fatalf("only NewDep should be wired:\n%s", stdout.String())
+	}
+}
+
+// ---------- 代理生成：同包与跨包接口 ----------
+func TestRun_Proxy(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	args := []string{"-proxy", "Calculator, vortice/cmd/vortice-gen/testdata/app/store.Store", "./testdata/proxy"}
+	if err := run(args, stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	src := stdout.String()
+	for _, want := range []string{
+		`"vortice/business/remote"`,
+		"type calculatorProxy struct{ remote.Invoker }",
+		"func (p calculatorProxy) Ping() error {\n\treturn p.Invoke(\"Ping\", nil)",
+		"err := p.Invoke(\"Price\", []any{a0, a1}, &r0)",
+		"func (p calculatorProxy) Quote(a0 string) (Order, *store.MemStore, error) {",
+		"return r0, r1, err",
+		"remote.RegisterProxy(func(inv remote.Invoker) Calculator { return calculatorProxy{inv} })",
+		"remote.RegisterProxy(func(inv remote.Invoker) store.Store { return storeProxy{inv} })",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %q:\n%s", want, src)
+		}
+	}
+	typeCheck(t, "testdata/proxy", "proxy_gen.go", stdout.Bytes())
+}
+
+// ---------- 代理生成失败：无法远程调用的方法全部报告 ----------
+func TestRun_ProxyErrors(t *testing.T) {
+	err := run([]string{"-proxy", "counter,Missing,Order", "./testdata/proxy"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil {
+		t.Fatalf("expected proxy generation to fail")
+	}
+	for _, want := range []string{
+		"Missing: type not found",
+		"Order: not an interface type",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error should contain %q: %v", want, err)
+		}
+	}
+	err = run([]string{"-proxy", "counter", "./testdata/proxy"}, &bytes.Buffer{}, &bytes.Buffer{})
+	for _, want := range []string{
+		"counter.Add: variadic methods",
+		"counter.Count: must return an error",
+		"counter.reset: unexported methods",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("error should contain %q: %v", want, err)
+		}
+	}
+}
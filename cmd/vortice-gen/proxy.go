@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// remotePkgPath is the import path of the package declaring RegisterProxy and Invoker.
+const remotePkgPath = "vortice/business/remote"
+
+// lookupInterfaces resolves the names of extension interfaces, given as Name for a type of the first package
+// or as importpath.Name for a type of any loaded package, reporting every name that cannot be resolved.
+func lookupInterfaces(pkgs []*packages.Package, names []string) ([]*types.Named, error) {
+	byPath := map[string]*types.Package{}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if pkg.Types != nil {
+			byPath[pkg.PkgPath] = pkg.Types
+		}
+	})
+	var (
+		ifaces []*types.Named
+		errs   []error
+	)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		pkg, typeName := pkgs[0].Types, name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			pkg, typeName = byPath[name[:i]], name[i+1:]
+			if pkg == nil {
+				errs = append(errs, fmt.Errorf("%s: package %s is not loaded", name, name[:i]))
+				continue
+			}
+		}
+		obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: type not found in %s", name, pkg.Path()))
+			continue
+		}
+		named, ok := types.Unalias(obj.Type()).(*types.Named)
+		if _, isIface := obj.Type().Underlying().(*types.Interface); !ok || !isIface {
+			errs = append(errs, fmt.Errorf("%s: not an interface type", name))
+			continue
+		}
+		ifaces = append(ifaces, named)
+	}
+	return ifaces, errors.Join(errs...)
+}
+
+// generateProxies renders a file for the output package implementing every interface by calling a
+// remote.Invoker, and registering each implementation with remote.RegisterProxy in an init function.
+// It reports every method the plugin protocol cannot carry.
+func generateProxies(out *types.Package, ifaces []*types.Named) ([]byte, error) {
+	imports := newImportSet(out)
+	remote := imports.qualify(types.NewPackage(remotePkgPath, "remote"))
+	if remote != "" {
+		remote += "."
+	}
+	used := map[string]bool{}
+	body := &bytes.Buffer{}
+	registrations := &bytes.Buffer{}
+	var errs []error
+	for _, iface := range ifaces {
+		if err := checkProxyInterface(out, iface); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ifaceType := types.TypeString(iface, imports.qualify)
+		proxy := proxyName(out, used, iface)
+		fmt.Fprintf(body, "// %s implements %s by invoking the extension in a plugin process.\n", proxy, ifaceType)
+		fmt.Fprintf(body, "type %s struct{ %sInvoker }\n", proxy, remote)
+		it := iface.Underlying().(*types.Interface)
+		for i := 0; i < it.NumMethods(); i++ {
+			writeProxyMethod(body, imports, proxy, it.Method(i))
+		}
+		fmt.Fprintf(body, "\n")
+		fmt.Fprintf(registrations, "\t%sRegisterProxy(func(inv %sInvoker) %s { return %s{inv} })\n",
+			remote, remote, ifaceType, proxy)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	fmt.Fprintf(body, "func init() {\n%s}\n", registrations)
+
+	src := &bytes.Buffer{}
+	fmt.Fprintf(src, "// Code generated by vortice-gen. DO NOT EDIT.\n\npackage %s\n\n", out.Name())
+	imports.write(src)
+	src.Write(body.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// checkProxyInterface returns an error listing every reason the interface cannot be proxied from package out:
+// the plugin Server only serves exported, non-variadic methods, and a proxy needs a trailing error result to
+// report failed calls.
+func checkProxyInterface(out *types.Package, iface *types.Named) error {
+	name := iface.Obj().Pkg().Path() + "." + iface.Obj().Name()
+	it := iface.Underlying().(*types.Interface)
+	var errs []error
+	switch {
+	case iface.TypeParams().Len() > 0:
+		errs = append(errs, fmt.Errorf("%s: generic interfaces cannot be proxied", name))
+	case !it.IsMethodSet():
+		errs = append(errs, fmt.Errorf("%s: constraint interfaces cannot be proxied", name))
+	case iface.Obj().Pkg() != out && !iface.Obj().Exported():
+		errs = append(errs, fmt.Errorf("%s: unexported interface cannot be referenced from package %s", name, out.Path()))
+	}
+	for i := 0; i < it.NumMethods(); i++ {
+		m := it.Method(i)
+		sig := m.Type().(*types.Signature)
+		results := sig.Results()
+		switch {
+		case !m.Exported():
+			errs = append(errs, fmt.Errorf("%s.%s: unexported methods cannot be invoked remotely", name, m.Name()))
+		case sig.Variadic():
+			errs = append(errs, fmt.Errorf("%s.%s: variadic methods cannot be invoked remotely", name, m.Name()))
+		case results.Len() == 0 || !types.Identical(results.At(results.Len()-1).Type(), errorType):
+			errs = append(errs, fmt.Errorf("%s.%s: must return an error as its last result to report failed calls",
+				name, m.Name()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// errorType is the type of the predeclared error interface.
+var errorType = types.Universe.Lookup("error").Type()
+
+// writeProxyMethod renders the method of the proxy type invoking m in the plugin process. The results other
+// than the trailing error are decoded into local variables.
+func writeProxyMethod(body *bytes.Buffer, imports *importSet, proxy string, m *types.Func) {
+	sig := m.Type().(*types.Signature)
+	var params, args, results, outs []string
+	for i := 0; i < sig.Params().Len(); i++ {
+		name := fmt.Sprintf("a%d", i)
+		params = append(params, name+" "+types.TypeString(sig.Params().At(i).Type(), imports.qualify))
+		args = append(args, name)
+	}
+	for i := 0; i < sig.Results().Len()-1; i++ {
+		results = append(results, types.TypeString(sig.Results().At(i).Type(), imports.qualify))
+		outs = append(outs, fmt.Sprintf("r%d", i))
+	}
+	invoke := fmt.Sprintf("p.Invoke(%q, nil", m.Name())
+	if len(args) > 0 {
+		invoke = fmt.Sprintf("p.Invoke(%q, []any{%s}", m.Name(), strings.Join(args, ", "))
+	}
+	for _, out := range outs {
+		invoke += ", &" + out
+	}
+	invoke += ")"
+	if len(outs) == 0 {
+		fmt.Fprintf(body, "\nfunc (p %s) %s(%s) error {\n", proxy, m.Name(), strings.Join(params, ", "))
+		fmt.Fprintf(body, "\treturn %s\n}\n", invoke)
+		return
+	}
+	fmt.Fprintf(body, "\nfunc (p %s) %s(%s) (%s, error) {\n", proxy, m.Name(), strings.Join(params, ", "),
+		strings.Join(results, ", "))
+	for i, out := range outs {
+		fmt.Fprintf(body, "\tvar %s %s\n", out, results[i])
+	}
+	fmt.Fprintf(body, "\terr := %s\n\treturn %s, err\n}\n", invoke, strings.Join(outs, ", "))
+}
+
+// proxyName derives an unexported type name for the proxy of iface, adding a suffix if it collides with a
+// declaration of package out or another proxy.
+func proxyName(out *types.Package, used map[string]bool, iface *types.Named) string {
+	r := []rune(iface.Obj().Name())
+	r[0] = unicode.ToLower(r[0])
+	base := string(r) + "Proxy"
+	name := base
+	for i := 2; used[name] || out.Scope().Lookup(name) != nil; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	used[name] = true
+	return name
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"vortice"
+	"vortice/business"
+	"vortice/cmd/vortice-gen/testdata/app/store"
+)
+
+type Config struct{ DSN string }
+
+type Service struct {
+	cfg   Config
+	store store.Store
+}
+
+type Greeter interface{ Greet() string }
+
+type greeter struct{ svc *Service }
+
+func (g *greeter) Greet() string { return "hello" }
+
+func newConfig() Config { return Config{DSN: "mem"} }
+
+func newService(cfg *Config, s store.Store) *Service { return &Service{cfg: *cfg, store: s} }
+
+func newGreeter(svc *Service) Greeter { return &greeter{svc: svc} }
+
+type Clock struct{ offset int }
+
+type Request struct {
+	clock *Clock
+	svc   *Service
+}
+
+type Handler struct{ req *Request }
+
+func newClock() Clock { return Clock{} }
+
+func newRequest(clock *Clock, svc *Service) *Request { return &Request{clock: clock, svc: svc} }
+
+func newHandler(req *Request) *Handler { return &Handler{req: req} }
+
+func init() {
+	vortice.Register2(newService)
+	business.RegisterExt1(newGreeter)
+	vortice.Register0(newConfig)
+	vortice.Register0(store.NewMemStore)
+	vortice.Register0(func() *greeter { return nil })
+	vortice.Register0(newClock, vortice.WithPrototype())
+	vortice.Register2(newRequest, vortice.WithDesc("request"), vortice.WithPrototype())
+	vortice.Register1(newHandler, vortice.WithPrototype(), vortice.WithSingleton())
+}
@@ -0,0 +1,15 @@
+package store
+
+// Store persists orders.
+type Store interface {
+	Save(id string) error
+}
+
+// MemStore is an in-memory Store.
+type MemStore struct{}
+
+// NewMemStore creates a MemStore.
+func NewMemStore() Store { return &MemStore{} }
+
+// Save implements Store.
+func (s *MemStore) Save(id string) error { return nil }
@@ -0,0 +1,29 @@
+package conditional
+
+import (
+	"os"
+
+	"vortice"
+)
+
+type DB struct{ dsn string }
+
+type Cache struct{}
+
+type Repo struct{ db *DB }
+
+func newDevDB() *DB  { return &DB{dsn: "dev"} }
+func newProdDB() *DB { return &DB{dsn: "prod"} }
+
+func newCache() *Cache         { return &Cache{} }
+func newFastCache() *Cache     { return &Cache{} }
+func newRepo(db *DB) *Repo     { return &Repo{db: db} }
+func useFast(vortice.Env) bool { return os.Getenv("FAST") != "" }
+
+func init() {
+	vortice.Register0(newDevDB, vortice.WithProfile("dev"))
+	vortice.Register0(newProdDB, vortice.WithProfile("prod"))
+	vortice.Register0(newCache, vortice.WithOnMissing[*Cache]())
+	vortice.Register0(newFastCache, vortice.WithCondition(useFast))
+	vortice.Register1(newRepo)
+}
@@ -0,0 +1,14 @@
+package cycle
+
+import "vortice"
+
+type A struct{}
+type B struct{}
+
+func newA(b *B) *A { return &A{} }
+func newB(a *A) *B { return &B{} }
+
+func init() {
+	vortice.Register1(newA)
+	vortice.Register1(newB)
+}
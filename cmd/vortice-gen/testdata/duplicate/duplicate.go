@@ -0,0 +1,13 @@
+package duplicate
+
+import "vortice"
+
+type A struct{}
+
+func newA() *A      { return &A{} }
+func newOtherA() *A { return &A{} }
+
+func init() {
+	vortice.Register0(newA)
+	vortice.Register0(newOtherA)
+}
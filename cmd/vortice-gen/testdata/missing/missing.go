@@ -0,0 +1,13 @@
+package missing
+
+import "vortice"
+
+type A struct{}
+type B struct{}
+type C struct{}
+
+func newA(b *B, c *C) *A { return &A{} }
+
+func init() {
+	vortice.Register2(newA)
+}
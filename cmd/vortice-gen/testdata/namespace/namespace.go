@@ -0,0 +1,33 @@
+package namespace
+
+import "vortice/business"
+
+type Greeter interface{ Greet() string }
+
+type greeter struct{ who string }
+
+func (g greeter) Greet() string { return "hello from " + g.who }
+
+func newGreeter() Greeter     { return greeter{"main"} }
+func newAcmeGreeter() Greeter { return greeter{"acme"} }
+func newBetaGreeter() Greeter { return greeter{"beta"} }
+
+func initBeta() error {
+	business.RegisterExt0(newBetaGreeter)
+	return nil
+}
+
+func init() {
+	business.RegisterExt0(newGreeter)
+
+	acme := business.NewPlugin("acme")
+	acme.Init(func() error {
+		business.RegisterExt0(newAcmeGreeter)
+		return nil
+	})
+	business.RegisterPlugin(acme)
+
+	beta := business.NewPlugin("beta")
+	beta.Init(initBeta)
+	business.RegisterPlugin(beta)
+}
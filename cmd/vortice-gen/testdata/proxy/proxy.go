@@ -0,0 +1,22 @@
+package proxy
+
+import "vortice/cmd/vortice-gen/testdata/app/store"
+
+type Order struct {
+	ID  string
+	Qty int
+}
+
+// Calculator prices orders.
+type Calculator interface {
+	Price(order *Order, discount float64) (int, error)
+	Quote(id string) (Order, *store.MemStore, error)
+	Ping() error
+}
+
+// counter cannot be proxied.
+type counter interface {
+	Count() int
+	Add(n ...int) error
+	reset() error
+}
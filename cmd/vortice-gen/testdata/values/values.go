@@ -0,0 +1,22 @@
+package values
+
+import "vortice"
+
+type Dep struct{}
+
+type Params struct {
+	vortice.In
+	Addr string `value:"http.addr"`
+	Dep  *Dep
+}
+
+type Server struct{}
+
+func NewDep() *Dep { return &Dep{} }
+
+func NewServer(p Params) *Server { return &Server{} }
+
+func init() {
+	vortice.Register0(NewDep)
+	vortice.Register1(NewServer)
+}
@@ -0,0 +1,66 @@
+// Package vorticegraph renders the component graph of a registry as Graphviz DOT, Mermaid or JSON.
+//
+// It is meant to be called from a test or from main once all components are registered:
+//
+//	func TestDumpGraph(t *testing.T) {
+//		err := vorticegraph.Run(container.DefaultCore(), []string{"-format=mermaid", "-o=graph.mmd"}, os.Stdout)
+//		...
+//	}
+package vorticegraph
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"vortice/object"
+)
+
+const (
+	// FormatDOT renders the graph in Graphviz DOT format.
+	FormatDOT = "dot"
+	// FormatMermaid renders the graph as a Mermaid flowchart.
+	FormatMermaid = "mermaid"
+	// FormatJSON renders the graph as JSON.
+	FormatJSON = "json"
+)
+
+// Write renders the registry's graph in the given format to w.
+func Write(reg object.DefinitionRegistry, format string, w io.Writer) error {
+	g := reg.Graph()
+	switch format {
+	case FormatDOT:
+		return g.WriteDOT(w)
+	case FormatMermaid:
+		return g.WriteMermaid(w)
+	case FormatJSON:
+		return g.WriteJSON(w)
+	default:
+		return fmt.Errorf("unknown graph format: %q", format)
+	}
+}
+
+// Run parses command line style arguments and renders the registry's graph.
+// Supported flags are -format (dot, mermaid or json, default dot) and -o (output file, default stdout).
+func Run(reg object.DefinitionRegistry, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("vortice-graph", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	format := fs.String("format", FormatDOT, "output format: dot, mermaid or json")
+	output := fs.String("o", "", "output file, stdout if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output == "" {
+		return Write(reg, *format, stdout)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := Write(reg, *format, f); err != nil {
+		return errors.Join(err, f.Close())
+	}
+	return f.Close()
+}
@@ -0,0 +1,60 @@
+package vorticegraph
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"vortice/object"
+)
+
+type graphDep struct{}
+type graphRoot struct{}
+
+func newGraphDep() *graphDep              { return &graphDep{} }
+func newGraphRoot(d *graphDep) *graphRoot { return &graphRoot{} }
+
+func newGraphRegistry(t *testing.T) object.DefinitionRegistry {
+	t.Helper()
+	reg := object.NewDefinitionRegistry()
+	for _, fn := range []any{newGraphDep, newGraphRoot} {
+		if _, err := reg.RegisterFactory(fn, object.NewProperty(), false); err != nil {
+			t.Fatalf("RegisterFactory failed: %v", err)
+		}
+	}
+	return reg
+}
+
+func TestWrite_Formats(t *testing.T) {
+	reg := newGraphRegistry(t)
+	for format, want := range map[string]string{
+		FormatDOT:     "digraph vortice",
+		FormatMermaid: "flowchart LR",
+		FormatJSON:    `"nodes"`,
+	} {
+		buf := &bytes.Buffer{}
+		if err := Write(reg, format, buf); err != nil {
+			t.Fatalf("%s: Write failed: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), want) || !strings.Contains(buf.String(), "newGraphRoot") {
+			t.Fatalf("%s: unexpected output:\n%s", format, buf.String())
+		}
+	}
+	if err := Write(reg, "svg", &bytes.Buffer{}); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
+
+func TestRun_OutputFile(t *testing.T) {
+	reg := newGraphRegistry(t)
+	out := filepath.Join(t.TempDir(), "graph.mmd")
+	if err := Run(reg, []string{"-format=mermaid", "-o=" + out}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil || !strings.Contains(string(data), "-->") {
+		t.Fatalf("expected mermaid edges in output file, got %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,15 @@
+// Command vortice-vet runs the vortice registration analyzer as a vet tool:
+//
+//	go install vortice/cmd/vortice-vet
+//	go vet -vettool=$(which vortice-vet) ./...
+package main
+
+import (
+	"vortice/lint"
+
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(lint.Analyzer)
+}
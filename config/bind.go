@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"vortice/util"
+)
+
+var (
+	// ErrBindTarget is the error returned when Bind is given anything but a non-nil pointer to a struct.
+	ErrBindTarget = errors.New("bind target must be a non-nil pointer to a struct")
+
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Bind fills the struct pointed to by out with the values under the section key, "" for the root.
+// Every exported field is read from the key section.name, where name is the `config` tag of the field or
+// its lower-cased name; a tag of "-" skips the field. Nested structs are bound from their own section.
+//
+// A field whose key is not set keeps the value of its `default` tag, if any, or is left unchanged.
+// The `validate` tag holds comma-separated rules checked after the value is set:
+//
+//	required   the key or a default must be set
+//	min=N      numbers must be at least N, durations such as 1s included; strings and slices must have
+//	           at least N elements
+//	max=N      numbers must be at most N; strings and slices must have at most N elements
+//	oneof=a b  the value must be one of the space-separated values
+//
+// Supported field types are strings, booleans, numbers, time.Duration, slices of those, read from lists
+// or comma-separated strings, and structs. Bind reports every failing key, naming it in full.
+func (c *Config) Bind(section string, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", ErrBindTarget, out)
+	}
+	return errors.Join(c.bindStruct(strings.ToLower(section), rv.Elem())...)
+}
+
+// bindStruct binds every exported field of the struct value v under prefix.
+func (c *Config) bindStruct(prefix string, v reflect.Value) []error {
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := field.Tag.Get("config")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		key := joinKey(prefix, strings.ToLower(name))
+		if field.Type.Kind() == reflect.Struct {
+			errs = append(errs, c.bindStruct(key, v.Field(i))...)
+			continue
+		}
+		raw, ok := c.Lookup(key)
+		if !ok {
+			raw, ok = field.Tag.Lookup("default")
+		}
+		if ok {
+			if err := util.SetValue(v.Field(i), raw); err != nil {
+				errs = append(errs, fmt.Errorf("config key %s: %w", key, err))
+				continue
+			}
+		}
+		if err := validate(v.Field(i), ok, field.Tag.Get("validate")); err != nil {
+			errs = append(errs, fmt.Errorf("config key %s: %w", key, err))
+		}
+	}
+	return errs
+}
+
+// Convert converts raw to typ following the rules of Bind, such as parsing numbers and durations from
+// strings and splitting comma-separated strings into slices; see util.Convert.
+func Convert(raw any, typ reflect.Type) (reflect.Value, error) {
+	return util.Convert(raw, typ)
+}
+
+// validate checks the rules of a validate tag against the field value; set reports whether a value
+// or a default was provided.
+func validate(v reflect.Value, set bool, rules string) error {
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "":
+		case "required":
+			if !set {
+				return errors.New("required but not set")
+			}
+		case "min", "max":
+			limit, err := parseLimit(v, arg)
+			if err != nil {
+				return fmt.Errorf("invalid rule %q: %w", rule, err)
+			}
+			n, unit, ok := measure(v)
+			if !ok {
+				return fmt.Errorf("rule %s does not apply to %s", name, v.Type())
+			}
+			var shown any = int(n)
+			if unit == "value" {
+				shown = v.Interface()
+			}
+			if name == "min" && n < limit {
+				return fmt.Errorf("%s %v is less than min %s", unit, shown, arg)
+			}
+			if name == "max" && n > limit {
+				return fmt.Errorf("%s %v is greater than max %s", unit, shown, arg)
+			}
+		case "oneof":
+			options := strings.Fields(arg)
+			if s := fmt.Sprint(v.Interface()); !slices.Contains(options, s) {
+				return fmt.Errorf("value %q is not one of %s", s, strings.Join(options, ", "))
+			}
+		default:
+			return fmt.Errorf("unknown validation rule %q", name)
+		}
+	}
+	return nil
+}
+
+// parseLimit parses the argument of min and max, as a duration for time.Duration fields.
+func parseLimit(v reflect.Value, arg string) (float64, error) {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(arg)
+		return float64(d), err
+	}
+	return strconv.ParseFloat(arg, 64)
+}
+
+// measure returns the number compared by min and max: the value of numbers and the length of strings and slices.
+func measure(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "value", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "value", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "value", true
+	case reflect.String, reflect.Slice:
+		return float64(v.Len()), "length", true
+	}
+	return 0, "", false
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type poolConfig struct {
+	Size int `validate:"min=1,max=64"`
+}
+
+type dbConfig struct {
+	Host     string        `validate:"required"`
+	Port     int           `default:"5432"`
+	Timeout  time.Duration `default:"1s" validate:"min=1s"`
+	Replicas []string
+	Pool     poolConfig
+	User     string `config:"username" default:"root"`
+	Ignored  string `config:"-"`
+	internal string
+}
+
+func TestBind_Files(t *testing.T) {
+	for _, path := range []string{"testdata/app.yaml", "testdata/app.json", "testdata/app.toml"} {
+		cfg := New(File(path))
+		if err := cfg.Load(); err != nil {
+			t.Fatalf("%s: load failed: %v", path, err)
+		}
+		var db dbConfig
+		if err := cfg.Bind("db", &db); err != nil {
+			t.Fatalf("%s: bind failed: %v", path, err)
+		}
+		if db.Host == "" || db.Port == 5432 || db.Timeout < 3*time.Second || len(db.Replicas) == 0 || db.Pool.Size == 0 {
+			t.Fatalf("%s: unexpected binding %+v", path, db)
+		}
+		if db.User != "root" {
+			t.Fatalf("%s: default should apply to unset keys, got %q", path, db.User)
+		}
+	}
+}
+
+// 环境变量覆盖文件，逗号分隔的字符串可绑定到切片
+func TestBind_Layered(t *testing.T) {
+	t.Setenv("APP_DB_REPLICAS", "a, b,c")
+	t.Setenv("APP_DB_PORT", "7000")
+	cfg := New(File("testdata/app.yaml"), Env("APP"))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	var db dbConfig
+	if err := cfg.Bind("DB", &db); err != nil {
+		t.Fatalf("bind failed: %v", err)
+	}
+	if db.Host != "yaml-host" || db.Port != 7000 || db.Timeout != 3*time.Second {
+		t.Fatalf("unexpected binding %+v", db)
+	}
+	if !reflect.DeepEqual(db.Replicas, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected replicas %v", db.Replicas)
+	}
+}
+
+// 错误信息应包含完整的键名，且一次报告所有错误
+func TestBind_Errors(t *testing.T) {
+	cfg := New(Map(map[string]any{
+		"db.port":      "not-a-number",
+		"db.timeout":   "10ms",
+		"db.pool.size": 100,
+	}))
+	var db dbConfig
+	err := cfg.Bind("db", &db)
+	if err == nil {
+		t.Fatalf("expected bind errors")
+	}
+	for _, want := range []string{
+		"config key db.host: required but not set",
+		"config key db.port:",
+		"config key db.timeout: value 10ms is less than min 1s",
+		"config key db.pool.size: value 100 is greater than max 64",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error should contain %q, got %v", want, err)
+		}
+	}
+}
+
+func TestBind_OneOf(t *testing.T) {
+	type appConfig struct {
+		Mode string `validate:"oneof=debug release"`
+	}
+	var app appConfig
+	if err := New(Map(map[string]any{"mode": "debug"})).Bind("", &app); err != nil || app.Mode != "debug" {
+		t.Fatalf("expected debug, got %q %v", app.Mode, err)
+	}
+	err := New(Map(map[string]any{"mode": "test"})).Bind("", &app)
+	if err == nil || !strings.Contains(err.Error(), `config key mode: value "test" is not one of debug, release`) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestBind_InvalidTarget(t *testing.T) {
+	cfg := New()
+	for _, out := range []any{nil, dbConfig{}, (*dbConfig)(nil), new(int)} {
+		if err := cfg.Bind("db", out); !errors.Is(err, ErrBindTarget) {
+			t.Fatalf("expected ErrBindTarget for %T, got %v", out, err)
+		}
+	}
+}
+
+// JSON 数字解码为 float64，大整数不应经过 1e+06 这样的字符串形式
+func TestBind_JSONNumbers(t *testing.T) {
+	type limitsConfig struct {
+		MaxConns int    `config:"max_conns"`
+		MaxBytes uint64 `config:"max_bytes"`
+		Ratio    float32
+		Label    string
+		Timeout  time.Duration
+	}
+	path := filepath.Join(t.TempDir(), "limits.json")
+	data := `{"db": {"max_conns": 1000000, "max_bytes": 10000000000, "ratio": 0.5, "label": 2000000, "timeout": 1000000000}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := New(File(path))
+	if err := cfg.Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	var limits limitsConfig
+	if err := cfg.Bind("db", &limits); err != nil {
+		t.Fatalf("bind failed: %v", err)
+	}
+	want := limitsConfig{MaxConns: 1000000, MaxBytes: 10000000000, Ratio: 0.5, Label: "2000000", Timeout: time.Second}
+	if limits != want {
+		t.Fatalf("expected %+v, got %+v", want, limits)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	v, err := Convert("1m30s", reflect.TypeOf(time.Duration(0)))
+	if err != nil || v.Interface() != 90*time.Second {
+		t.Fatalf("expected 1m30s, got %v %v", v, err)
+	}
+	v, err = Convert(float64(8080), reflect.TypeOf(0))
+	if err != nil || v.Interface() != 8080 {
+		t.Fatalf("expected 8080, got %v %v", v, err)
+	}
+	if _, err := Convert("abc", reflect.TypeOf(0)); err == nil {
+		t.Fatalf("expected a conversion error")
+	}
+}
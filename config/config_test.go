@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// 后添加的源优先级更高
+func TestConfig_LaterSourcesWin(t *testing.T) {
+	cfg := New(Map(map[string]any{"db.host": "a", "db.port": 1}))
+	cfg.Add(Map(map[string]any{"db": map[string]any{"host": "b"}}))
+	if v, ok := cfg.Lookup("db.host"); !ok || v != "b" {
+		t.Fatalf("expected the later source to win, got %v %v", v, ok)
+	}
+	if v, ok := cfg.Lookup("DB.Port"); !ok || v != 1 {
+		t.Fatalf("keys missing from later sources should fall back, got %v %v", v, ok)
+	}
+	if _, ok := cfg.Lookup("db.user"); ok {
+		t.Fatalf("unknown key should not be found")
+	}
+	if len(cfg.Sources()) != 2 {
+		t.Fatalf("expected 2 sources, got %d", len(cfg.Sources()))
+	}
+}
+
+func TestConfig_LoadReportsEverySource(t *testing.T) {
+	cfg := New(File("testdata/missing.yaml"), File("testdata/broken.yaml"), File("testdata/app.yaml"))
+	err := cfg.Load()
+	if err == nil {
+		t.Fatalf("expected load errors")
+	}
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) || len(joined.Unwrap()) != 2 {
+		t.Fatalf("expected one error per failing file, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "testdata/missing.yaml") || !strings.Contains(err.Error(), "testdata/broken.yaml") {
+		t.Fatalf("errors should name the files, got %v", err)
+	}
+	if v, _ := cfg.Lookup("db.host"); v != "yaml-host" {
+		t.Fatalf("valid files should still be loaded, got %v", v)
+	}
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vortice/util"
+
+	"go.uber.org/zap"
+)
+
+// Reloadable holds the latest binding of a configuration section and notifies listeners when it changes.
+// Values are replaced, never modified, so a value returned by Load may be kept and read without locking.
+type Reloadable[T any] struct {
+	cfg       *Config
+	section   string
+	value     atomic.Pointer[T]
+	mutex     *sync.Mutex
+	bound     bool
+	listeners []func(old, new T)
+}
+
+// NewReloadable creates a Reloadable for the section of cfg, rebound by every Config.Reload. It holds
+// the zero value of T until the first successful Update or Reload.
+func NewReloadable[T any](cfg *Config, section string) *Reloadable[T] {
+	r := &Reloadable[T]{cfg: cfg, section: section, mutex: &sync.Mutex{}}
+	r.value.Store(new(T))
+	cfg.onReload(r.Update)
+	return r
+}
+
+// Section returns the configuration section the Reloadable is bound from.
+func (r *Reloadable[T]) Section() string {
+	return r.section
+}
+
+// Load returns the current value.
+func (r *Reloadable[T]) Load() *T {
+	return r.value.Load()
+}
+
+// Get returns a copy of the current value.
+func (r *Reloadable[T]) Get() T {
+	return *r.value.Load()
+}
+
+// OnChange registers fn to be called with the previous and the new value after every change. Listeners run
+// in registration order on the goroutine updating the value, without any lock held, so they may call back
+// into the Reloadable; the first binding does not notify.
+func (r *Reloadable[T]) OnChange(fn func(old, new T)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Reload loads the sources of the Config again and rebinds every Reloadable of it, including this one.
+func (r *Reloadable[T]) Reload() error {
+	return r.cfg.Reload()
+}
+
+// Update binds the section from the current values of the Config and, if the result differs from the
+// current value, swaps it in and notifies the listeners registered at that time once the swap is done.
+// If binding fails the current value is kept.
+func (r *Reloadable[T]) Update() error {
+	old, next, listeners, err := r.swap()
+	if err != nil || listeners == nil {
+		return err
+	}
+	for _, fn := range listeners {
+		fn(*old, *next)
+	}
+	return nil
+}
+
+// swap binds the section and stores the result if it differs from the current value, returning both values
+// and a copy of the listeners to notify, nil if there is nothing to notify.
+func (r *Reloadable[T]) swap() (old, next *T, listeners []func(old, new T), err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	next = new(T)
+	if err := r.cfg.Bind(r.section, next); err != nil {
+		return nil, nil, nil, err
+	}
+	old = r.value.Load()
+	if r.bound && reflect.DeepEqual(old, next) {
+		return nil, nil, nil, nil
+	}
+	r.value.Store(next)
+	if !r.bound {
+		r.bound = true
+		return nil, nil, nil, nil
+	}
+	return old, next, append([]func(old, new T){}, r.listeners...), nil
+}
+
+// onReload registers fn to be called by Reload after the sources are loaded.
+func (c *Config) onReload(fn func() error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reloaders = append(c.reloaders, fn)
+}
+
+// Reload loads the sources again and rebinds every Reloadable created from the Config. Sources failing
+// to load keep their previous values, so the Reloadables are still rebound from the others.
+func (c *Config) Reload() error {
+	errs := []error{c.Load()}
+	c.mutex.RLock()
+	reloaders := append([]func() error{}, c.reloaders...)
+	c.mutex.RUnlock()
+	for _, fn := range reloaders {
+		errs = append(errs, fn())
+	}
+	return errors.Join(errs...)
+}
+
+// Watch polls the files of every FileSource of the Config at the interval and calls Reload when any of them
+// is modified, until ctx is done. Reload errors are logged.
+func (c *Config) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	stamps := c.fileStamps()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		next := c.fileStamps()
+		if reflect.DeepEqual(stamps, next) {
+			continue
+		}
+		stamps = next
+		if err := c.Reload(); err != nil {
+			util.Logger().Error("config reload failed", zap.Error(err))
+		}
+	}
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// fileStamps returns the stamps of the files of the FileSources, a missing file having the zero stamp.
+func (c *Config) fileStamps() map[string]fileStamp {
+	stamps := map[string]fileStamp{}
+	for _, src := range c.Sources() {
+		if file, ok := src.(*FileSource); ok {
+			if info, err := os.Stat(file.Path()); err == nil {
+				stamps[file.Path()] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+			} else {
+				stamps[file.Path()] = fileStamp{}
+			}
+		}
+	}
+	return stamps
+}
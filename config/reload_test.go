@@ -0,0 +1,140 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type limitConfig struct {
+	Rate int `validate:"min=1"`
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
+
+// 首次绑定不触发回调，之后每次变化触发一次
+func TestReloadable_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.yaml")
+	writeFile(t, path, "limits:\n  rate: 10\n")
+	cfg := New(File(path))
+	r := NewReloadable[limitConfig](cfg, "limits")
+	var changes [][2]int
+	r.OnChange(func(old, new limitConfig) { changes = append(changes, [2]int{old.Rate, new.Rate}) })
+	if err := r.Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if r.Get().Rate != 10 || len(changes) != 0 {
+		t.Fatalf("first binding should not notify, got %v %v", r.Get(), changes)
+	}
+	first := r.Load()
+
+	writeFile(t, path, "limits:\n  rate: 20\n")
+	if err := r.Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if r.Get().Rate != 20 || len(changes) != 1 || changes[0] != [2]int{10, 20} {
+		t.Fatalf("expected one change 10 -> 20, got %v", changes)
+	}
+	if first.Rate != 10 {
+		t.Fatalf("previous values must not be modified")
+	}
+	// 值未变化时不触发
+	if err := r.Reload(); err != nil || len(changes) != 1 {
+		t.Fatalf("unchanged reload should not notify, got %v %v", changes, err)
+	}
+}
+
+// 绑定失败时保留旧值并返回包含键名的错误
+func TestReloadable_InvalidKeepsValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.json")
+	writeFile(t, path, `{"limits": {"rate": 5}}`)
+	cfg := New(File(path))
+	r := NewReloadable[limitConfig](cfg, "limits")
+	if err := cfg.Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	writeFile(t, path, `{"limits": {"rate": 0}}`)
+	err := r.Reload()
+	if err == nil || !strings.Contains(err.Error(), "config key limits.rate") {
+		t.Fatalf("expected a validation error naming the key, got %v", err)
+	}
+	if r.Get().Rate != 5 {
+		t.Fatalf("invalid configuration should keep the current value, got %v", r.Get())
+	}
+}
+
+// 回调中可以重新进入同一个 Reloadable，不会死锁
+func TestReloadable_ReentrantListener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.yaml")
+	writeFile(t, path, "limits:\n  rate: 1\n")
+	cfg := New(File(path))
+	r := NewReloadable[limitConfig](cfg, "limits")
+	if err := r.Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	var seen []int
+	late := 0
+	r.OnChange(func(_, new limitConfig) {
+		seen = append(seen, r.Get().Rate)
+		if err := r.Update(); err != nil {
+			t.Errorf("update from listener failed: %v", err)
+		}
+		r.OnChange(func(_, new limitConfig) { late = new.Rate })
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		writeFile(t, path, "limits:\n  rate: 2\n")
+		done <- r.Reload()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("reload failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("listener calling back into the Reloadable deadlocked")
+	}
+	if len(seen) != 1 || seen[0] != 2 || late != 0 {
+		t.Fatalf("expected one notification seeing rate 2 and no late listener call, got %v %d", seen, late)
+	}
+}
+
+func TestConfig_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.yaml")
+	writeFile(t, path, "limits:\n  rate: 1\n")
+	cfg := New(File(path))
+	r := NewReloadable[limitConfig](cfg, "limits")
+	if err := cfg.Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	changed := make(chan limitConfig, 1)
+	r.OnChange(func(_, new limitConfig) { changed <- new })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		cfg.Watch(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	writeFile(t, path, "limits:\n  rate: 200\n")
+	select {
+	case v := <-changed:
+		if v.Rate != 200 {
+			t.Fatalf("expected rate 200, got %d", v.Rate)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("watch did not pick up the change")
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// decoders maps file extensions to the function decoding the file content.
+	decoders = map[string]func(data []byte, out *map[string]any) error{
+		".yaml": func(data []byte, out *map[string]any) error { return yaml.Unmarshal(data, out) },
+		".yml":  func(data []byte, out *map[string]any) error { return yaml.Unmarshal(data, out) },
+		".json": func(data []byte, out *map[string]any) error { return json.Unmarshal(data, out) },
+		".toml": func(data []byte, out *map[string]any) error { return toml.Unmarshal(data, out) },
+	}
+)
+
+// FileSource provides the values of a YAML, JSON or TOML file, chosen by the file extension.
+// The file is read by Load; until then the source is empty.
+type FileSource struct {
+	path   string
+	mutex  *sync.RWMutex
+	values map[string]any
+}
+
+// File creates a FileSource for the file at path.
+func File(path string) *FileSource {
+	return &FileSource{path: path, mutex: &sync.RWMutex{}, values: map[string]any{}}
+}
+
+// Name returns the path of the file.
+func (s *FileSource) Name() string {
+	return "file " + s.path
+}
+
+// Path returns the path of the file.
+func (s *FileSource) Path() string {
+	return s.path
+}
+
+// Load reads and decodes the file, replacing the values loaded before only if it succeeds.
+func (s *FileSource) Load() error {
+	decode, ok := decoders[strings.ToLower(filepath.Ext(s.path))]
+	if !ok {
+		return fmt.Errorf("unsupported file extension %q", filepath.Ext(s.path))
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	raw := map[string]any{}
+	if err := decode(data, &raw); err != nil {
+		return err
+	}
+	values := map[string]any{}
+	flatten("", raw, values)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.values = values
+	return nil
+}
+
+// Lookup returns the value of the key.
+func (s *FileSource) Lookup(key string) (any, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// mapSource provides the values of an in-memory map.
+type mapSource struct {
+	values map[string]any
+}
+
+// Map creates a Source from a map. Nested maps are flattened into dotted keys, so
+// {"db": {"host": "x"}} and {"db.host": "x"} are equivalent.
+func Map(m map[string]any) Source {
+	values := map[string]any{}
+	flatten("", m, values)
+	return &mapSource{values: values}
+}
+
+// Name returns "map".
+func (s *mapSource) Name() string {
+	return "map"
+}
+
+// Lookup returns the value of the key.
+func (s *mapSource) Lookup(key string) (any, bool) {
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// envSource provides the values of environment variables.
+type envSource struct {
+	prefix string
+}
+
+// Env creates a Source reading environment variables when looked up. The key "db.max_conns" is read
+// from PREFIX_DB_MAX_CONNS, or from DB_MAX_CONNS if prefix is empty.
+func Env(prefix string) Source {
+	return &envSource{prefix: strings.ToUpper(prefix)}
+}
+
+// Name returns a description of the variables read.
+func (s *envSource) Name() string {
+	if s.prefix == "" {
+		return "env"
+	}
+	return "env " + s.prefix + "_*"
+}
+
+// Lookup returns the value of the environment variable of the key.
+func (s *envSource) Lookup(key string) (any, bool) {
+	name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	if s.prefix != "" {
+		name = s.prefix + "_" + name
+	}
+	return os.LookupEnv(name)
+}
+
+// flagSource provides the values of the flags set on a flag.FlagSet.
+type flagSource struct {
+	fs *flag.FlagSet
+}
+
+// Flags creates a Source from the flags explicitly set on fs, looked up by flag name, such as -db.host.
+// Flags left at their default do not override other sources.
+func Flags(fs *flag.FlagSet) Source {
+	return &flagSource{fs: fs}
+}
+
+// Name returns a description of the flag set.
+func (s *flagSource) Name() string {
+	return "flags " + s.fs.Name()
+}
+
+// Lookup returns the value of the flag named after the key, if set.
+func (s *flagSource) Lookup(key string) (any, bool) {
+	var value any
+	found := false
+	s.fs.Visit(func(f *flag.Flag) {
+		if strings.ToLower(f.Name) != key {
+			return
+		}
+		found = true
+		if getter, ok := f.Value.(flag.Getter); ok {
+			value = getter.Get()
+		} else {
+			value = f.Value.String()
+		}
+	})
+	return value, found
+}
+
+// flatten copies the leaves of the nested map m into out under lower-cased dotted keys.
+func flatten(prefix string, m map[string]any, out map[string]any) {
+	for k, v := range m {
+		key := joinKey(prefix, strings.ToLower(k))
+		switch child := v.(type) {
+		case map[string]any:
+			flatten(key, child, out)
+		case map[any]any:
+			converted := make(map[string]any, len(child))
+			for ck, cv := range child {
+				converted[fmt.Sprint(ck)] = cv
+			}
+			flatten(key, converted, out)
+		default:
+			out[key] = v
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"flag"
+	"testing"
+)
+
+// 三种文件格式应得到相同的扁平化键
+func TestFile_Formats(t *testing.T) {
+	for path, host := range map[string]string{
+		"testdata/app.yaml": "yaml-host",
+		"testdata/app.json": "json-host",
+		"testdata/app.toml": "toml-host",
+	} {
+		src := File(path)
+		if _, ok := src.Lookup("db.host"); ok {
+			t.Fatalf("%s: values should not be available before Load", path)
+		}
+		if err := src.Load(); err != nil {
+			t.Fatalf("%s: load failed: %v", path, err)
+		}
+		if v, ok := src.Lookup("db.host"); !ok || v != host {
+			t.Fatalf("%s: expected %s, got %v", path, host, v)
+		}
+		if _, ok := src.Lookup("db.pool.size"); !ok {
+			t.Fatalf("%s: nested keys should be flattened and lower-cased", path)
+		}
+	}
+}
+
+func TestFile_UnsupportedExtension(t *testing.T) {
+	if err := File("testdata/app.ini").Load(); err == nil {
+		t.Fatalf("expected an error for an unsupported extension")
+	}
+}
+
+// 加载失败时保留之前的值
+func TestFile_KeepsValuesOnFailedReload(t *testing.T) {
+	src := File("testdata/app.yaml")
+	if err := src.Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	src.path = "testdata/broken.yaml"
+	if err := src.Load(); err == nil {
+		t.Fatalf("expected a decode error")
+	}
+	if v, _ := src.Lookup("db.host"); v != "yaml-host" {
+		t.Fatalf("failed load should keep the previous values, got %v", v)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("APP_DB_MAX_CONNS", "10")
+	t.Setenv("DB_HOST", "env-host")
+	if v, ok := Env("app").Lookup("db.max_conns"); !ok || v != "10" {
+		t.Fatalf("expected APP_DB_MAX_CONNS, got %v %v", v, ok)
+	}
+	if v, ok := Env("").Lookup("db.host"); !ok || v != "env-host" {
+		t.Fatalf("expected DB_HOST, got %v %v", v, ok)
+	}
+	if _, ok := Env("app").Lookup("db.host"); ok {
+		t.Fatalf("prefixed source should not read unprefixed variables")
+	}
+}
+
+// 只有显式设置的 flag 才覆盖其他源
+func TestFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("db.host", "default-host", "")
+	fs.Int("db.port", 5432, "")
+	if err := fs.Parse([]string{"-db.port=6000"}); err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	src := Flags(fs)
+	if _, ok := src.Lookup("db.host"); ok {
+		t.Fatalf("flags left at their default should not be found")
+	}
+	if v, ok := src.Lookup("db.port"); !ok || v != 6000 {
+		t.Fatalf("expected 6000, got %v %v", v, ok)
+	}
+}
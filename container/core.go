@@ -3,6 +3,7 @@ package container
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,20 +23,57 @@ func DefaultCore() *Core {
 	return core
 }
 
+// Option configures a Core created by NewCore or Core.NewChild.
+type Option func(*Core)
+
+// WithContext sets the context of the Core; a child Core defaults to its parent's context.
+func WithContext(ctx context.Context) Option {
+	return func(c *Core) {
+		c.Context = ctx
+	}
+}
+
+// WithStartupTimeout sets how long Start waits for each service to start.
+func WithStartupTimeout(timeout time.Duration) Option {
+	return func(c *Core) {
+		c.lcp = newLifecycleProcessor(timeout)
+	}
+}
+
 // Core is the main structure that encapsulates the context, object factory, and lifecycle processor.
 type Core struct {
 	context.Context
 	ObjectFactory
-	lcp *lifecycleProcessor
+	lcp      *lifecycleProcessor
+	shutdown atomic.Bool
 }
 
 // NewCore initializes and returns a new Core instance with the provided context, setting up an object factory and lifecycle processor.
-func NewCore(ctx context.Context) *Core {
-	return &Core{
+func NewCore(ctx context.Context, opts ...Option) *Core {
+	c := &Core{
 		Context:       ctx,
 		ObjectFactory: NewCoreObjectFactory(),
 		lcp:           newLifecycleProcessor(DefaultStartupTimeout),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NewChild creates a child Core whose registry can add or override definitions and whose lookups fall back
+// to this Core for anything it does not define. The child has its own objects, EventBus and lifecycle:
+// it must be initialized and started separately, and shutting it down leaves this Core untouched.
+func (c *Core) NewChild(opts ...Option) *Core {
+	child := &Core{
+		Context:       c.Context,
+		ObjectFactory: c.ObjectFactory.NewChild(),
+		lcp:           newLifecycleProcessor(c.lcp.timeout),
+	}
+	for _, opt := range opts {
+		opt(child)
+	}
+	return child
 }
 
 // Init initializes the Core's ObjectFactory, preparing it for use and returns an error if initialization fails.
@@ -48,8 +86,13 @@ func (c *Core) Start() error {
 	return c.lcp.start(c.Context, c.ObjectFactory)
 }
 
-// Shutdown stops all running services and cleans up resources, finalizing the Core.
+// Shutdown publishes a ContainerShutdownEvent, then stops all running services and cleans up resources, finalizing the Core.
+// Only the first call has an effect.
 func (c *Core) Shutdown() {
+	if !c.shutdown.CompareAndSwap(false, true) {
+		return
+	}
+	c.EventBus().Publish(ContainerShutdownEvent{})
 	c.lcp.stop(c.Context)
 	c.ObjectFactory.Destroy()
 }
@@ -3,6 +3,9 @@ package container
 import (
 	"context"
 	"testing"
+	"time"
+
+	"vortice/object"
 )
 
 func TestDefaultCore_Singleton(t *testing.T) {
@@ -41,3 +44,97 @@ func TestCore_Init_Start_Shutdown_NoServices(t *testing.T) {
 }
 
 
+
+// ---------- 子容器：父级回退、覆盖与独立生命周期 ----------
+func registerAutowired(t *testing.T, c *Core, fn any) {
+	t.Helper()
+	p := object.NewProperty()
+	addAutowired(p)
+	if _, err := c.RegisterFactory(fn, p, true); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+}
+
+func TestCore_NewChild_FallbackToParent(t *testing.T) {
+	parent := NewCore(context.Background())
+	registerAutowired(t, parent, newCompC)
+	if err := parent.Init(); err != nil {
+		t.Fatalf("parent init failed: %v", err)
+	}
+	child := parent.NewChild()
+	registerAutowired(t, child, newCompB)
+	registerAutowired(t, child, newCompA)
+	if err := child.Init(); err != nil {
+		t.Fatalf("child init failed: %v", err)
+	}
+	objs, err := child.GetObjects(newTestCtx(), (*compA)(nil))
+	if err != nil || len(objs) != 1 {
+		t.Fatalf("get compA from child failed: %v", err)
+	}
+	pc, err := parent.GetObjects(newTestCtx(), (*compC)(nil))
+	if err != nil || len(pc) != 1 {
+		t.Fatalf("get compC from parent failed: %v", err)
+	}
+	if objs[0].Instance().(*compA).b.c != pc[0].Instance().(*compC) {
+		t.Fatalf("child should reuse the parent's singleton")
+	}
+	cc, err := child.GetObjects(newTestCtx(), (*compC)(nil))
+	if err != nil || len(cc) != 1 || cc[0] != pc[0] {
+		t.Fatalf("child lookup should fall back to the parent's object: %v", err)
+	}
+	if _, err := parent.GetObjects(newTestCtx(), (*compB)(nil)); err == nil {
+		t.Fatalf("parent must not see child definitions")
+	}
+	if defs := child.GetDefinitions(); len(defs) != 2 {
+		t.Fatalf("child GetDefinitions should return only its own definitions, got %d", len(defs))
+	}
+
+	child.Shutdown()
+	if !pc[0].Alive() {
+		t.Fatalf("shutting down the child must not destroy parent objects")
+	}
+	parent.Shutdown()
+}
+
+func TestCore_NewChild_Override(t *testing.T) {
+	parent := NewCore(context.Background())
+	registerAutowired(t, parent, newCompC)
+	registerAutowired(t, parent, newCompB)
+	if err := parent.Init(); err != nil {
+		t.Fatalf("parent init failed: %v", err)
+	}
+	child := parent.NewChild(WithStartupTimeout(time.Second))
+	registerAutowired(t, child, newCompC1)
+	registerAutowired(t, child, newCompA)
+	if err := child.Init(); err != nil {
+		t.Fatalf("child init failed: %v", err)
+	}
+	cc, err := child.GetObjects(newTestCtx(), (*compC)(nil))
+	if err != nil || len(cc) != 1 || cc[0].Definition().Factory().Name() != "vortice/container.newCompC1" {
+		t.Fatalf("child definition should override the parent's: %v", err)
+	}
+	pc, _ := parent.GetObjects(newTestCtx(), (*compC)(nil))
+	if pc[0].Definition().Factory().Name() != "vortice/container.newCompC" {
+		t.Fatalf("parent must keep its own definition")
+	}
+	a, err := child.GetObjects(newTestCtx(), (*compA)(nil))
+	if err != nil || len(a) != 1 {
+		t.Fatalf("get compA failed: %v", err)
+	}
+	// compB 属于父容器，仍然依赖父容器的 compC
+	if a[0].Instance().(*compA).b.c != pc[0].Instance().(*compC) {
+		t.Fatalf("parent definitions should be wired by the parent")
+	}
+}
+
+func TestCore_NewChild_MissingDependency(t *testing.T) {
+	parent := NewCore(context.Background())
+	if err := parent.Init(); err != nil {
+		t.Fatalf("parent init failed: %v", err)
+	}
+	child := parent.NewChild()
+	registerAutowired(t, child, newCompB)
+	if err := child.Init(); err == nil {
+		t.Fatalf("child init should fail when neither child nor parent provides a dependency")
+	}
+}
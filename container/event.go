@@ -0,0 +1,170 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"vortice/util"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// listenerMethodName is the name of the method a component must expose to be auto-subscribed.
+	listenerMethodName = "OnEvent"
+)
+
+type (
+	// Listener is implemented by components that want to receive events of type E.
+	// Singleton components implementing it are subscribed automatically when the container initializes.
+	Listener[E any] interface {
+		// OnEvent handles a single event published on the EventBus.
+		OnEvent(event E)
+	}
+	// ContainerInitializedEvent is published once all singleton objects have been created.
+	ContainerInitializedEvent struct{}
+	// ContainerStartedEvent is published once all auto-startup services have been started.
+	ContainerStartedEvent struct{}
+	// ContainerShutdownEvent is published when the container begins shutting down, before any service is stopped.
+	ContainerShutdownEvent struct{}
+)
+
+// subscriber holds an event handler together with the event type it accepts.
+type subscriber struct {
+	id      uint64
+	typ     reflect.Type
+	handler func(any)
+}
+
+// accepts reports whether the subscriber should receive an event of the given type.
+func (s *subscriber) accepts(rt reflect.Type) bool {
+	if s.typ == rt {
+		return true
+	}
+	return s.typ.Kind() == reflect.Interface && rt.Implements(s.typ)
+}
+
+// EventBus dispatches events to subscribers keyed by the event's type, either synchronously or asynchronously.
+type EventBus struct {
+	mux  *sync.RWMutex
+	wg   *sync.WaitGroup
+	seq  uint64
+	subs []*subscriber
+}
+
+// NewEventBus creates an empty EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		mux:  &sync.RWMutex{},
+		wg:   &sync.WaitGroup{},
+		subs: []*subscriber{},
+	}
+}
+
+// Subscribe registers fn for events of type E on the bus and returns a function that removes the subscription.
+func Subscribe[E any](bus *EventBus, fn func(event E)) func() {
+	typ := reflect.TypeOf((*E)(nil)).Elem()
+	return bus.subscribe(typ, func(event any) {
+		fn(event.(E))
+	})
+}
+
+// Publish delivers the event synchronously to every matching subscriber in subscription order.
+// A panicking subscriber is logged and does not prevent delivery to the others.
+func (b *EventBus) Publish(event any) {
+	for _, sub := range b.match(event) {
+		b.deliver(sub, event)
+	}
+}
+
+// PublishAsync delivers the event to every matching subscriber on its own goroutine and returns immediately.
+// Use Wait to block until all asynchronous deliveries have completed.
+func (b *EventBus) PublishAsync(event any) {
+	for _, sub := range b.match(event) {
+		b.wg.Add(1)
+		go func(sub *subscriber) {
+			defer b.wg.Done()
+			b.deliver(sub, event)
+		}(sub)
+	}
+}
+
+// Wait blocks until all events published with PublishAsync have been delivered.
+func (b *EventBus) Wait() {
+	b.wg.Wait()
+}
+
+// subscribe adds a handler for the given event type and returns its unsubscribe function.
+func (b *EventBus) subscribe(typ reflect.Type, handler func(any)) func() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.seq++
+	id := b.seq
+	b.subs = append(b.subs, &subscriber{id: id, typ: typ, handler: handler})
+	return func() {
+		b.unsubscribe(id)
+	}
+}
+
+// unsubscribe removes the subscriber with the given id, if present.
+func (b *EventBus) unsubscribe(id uint64) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	for i, sub := range b.subs {
+		if sub.id == id {
+			b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// subscribeObject subscribes the object's instance if it implements Listener for some event type.
+func (b *EventBus) subscribeObject(obj Object) bool {
+	rv := obj.Value()
+	if !rv.IsValid() {
+		return false
+	}
+	method := rv.MethodByName(listenerMethodName)
+	if !method.IsValid() {
+		return false
+	}
+	mt := method.Type()
+	if mt.NumIn() != 1 || mt.NumOut() != 0 {
+		return false
+	}
+	b.subscribe(mt.In(0), func(event any) {
+		method.Call([]reflect.Value{reflect.ValueOf(event)})
+	})
+	return true
+}
+
+// match returns a snapshot of the subscribers accepting the event.
+func (b *EventBus) match(event any) []*subscriber {
+	if event == nil {
+		return nil
+	}
+	rt := reflect.TypeOf(event)
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	var subs []*subscriber
+	for _, sub := range b.subs {
+		if sub.accepts(rt) {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// deliver invokes the subscriber's handler, recovering and logging any panic.
+func (b *EventBus) deliver(sub *subscriber, event any) {
+	defer func() {
+		if r := recover(); r != nil {
+			util.Logger().Error("event listener panicked",
+				zap.String("event", fmt.Sprintf("%T", event)),
+				zap.Any("panic", r), zap.ByteString("stack", debug.Stack()))
+		}
+	}()
+	sub.handler(event)
+}
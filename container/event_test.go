@@ -0,0 +1,137 @@
+package container
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"vortice/object"
+)
+
+type orderPlaced struct{ id int }
+
+type namedEvent interface{ EventName() string }
+
+func (e orderPlaced) EventName() string { return "orderPlaced" }
+
+// ---------- 自动订阅的监听器组件 ----------
+type lifecycleListener struct {
+	initialized, started, shutdown int
+}
+
+func newLifecycleListener() *lifecycleListener { return &lifecycleListener{} }
+
+func (l *lifecycleListener) OnEvent(event any) {
+	switch event.(type) {
+	case ContainerInitializedEvent:
+		l.initialized++
+	case ContainerStartedEvent:
+		l.started++
+	case ContainerShutdownEvent:
+		l.shutdown++
+	}
+}
+
+type orderListener struct{ got []int }
+
+func newOrderListener() *orderListener             { return &orderListener{} }
+func (l *orderListener) OnEvent(event orderPlaced) { l.got = append(l.got, event.id) }
+
+func TestEventBus_PublishSync(t *testing.T) {
+	bus := NewEventBus()
+	var got []int
+	Subscribe(bus, func(e orderPlaced) { got = append(got, e.id) })
+	bus.Publish(orderPlaced{id: 1})
+	bus.Publish(struct{}{}) // 类型不匹配，不应投递
+	if len(got) != 1 || got[0] != 1 {
+		t.Fatalf("unexpected deliveries: %v", got)
+	}
+}
+
+func TestEventBus_InterfaceSubscriber(t *testing.T) {
+	bus := NewEventBus()
+	var names []string
+	Subscribe(bus, func(e namedEvent) { names = append(names, e.EventName()) })
+	bus.Publish(orderPlaced{id: 2})
+	if len(names) != 1 || names[0] != "orderPlaced" {
+		t.Fatalf("interface subscriber should receive implementing events, got %v", names)
+	}
+}
+
+func TestEventBus_Unsubscribe(t *testing.T) {
+	bus := NewEventBus()
+	calls := 0
+	cancel := Subscribe(bus, func(e orderPlaced) { calls++ })
+	bus.Publish(orderPlaced{})
+	cancel()
+	bus.Publish(orderPlaced{})
+	if calls != 1 {
+		t.Fatalf("expected 1 call after unsubscribe, got %d", calls)
+	}
+}
+
+func TestEventBus_PublishAsync(t *testing.T) {
+	bus := NewEventBus()
+	var calls atomic.Int32
+	for i := 0; i < 3; i++ {
+		Subscribe(bus, func(e orderPlaced) { calls.Add(1) })
+	}
+	bus.PublishAsync(orderPlaced{})
+	bus.Wait()
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 async deliveries, got %d", calls.Load())
+	}
+}
+
+func TestEventBus_PanicIsolated(t *testing.T) {
+	bus := NewEventBus()
+	delivered := false
+	Subscribe(bus, func(e orderPlaced) { panic("boom") })
+	Subscribe(bus, func(e orderPlaced) { delivered = true })
+	bus.Publish(orderPlaced{})
+	if !delivered {
+		t.Fatalf("panicking listener should not block later listeners")
+	}
+}
+
+// ---------- Core 生命周期事件与 Listener 自动订阅 ----------
+func TestCore_LifecycleEventsAndAutoSubscribe(t *testing.T) {
+	c := NewCore(context.Background())
+	p := object.NewProperty()
+	addAutowired(p)
+	if _, err := c.RegisterFactory(newLifecycleListener, p, false); err != nil {
+		t.Fatalf("register listener failed: %v", err)
+	}
+	po := object.NewProperty()
+	addAutowired(po)
+	if _, err := c.RegisterFactory(newOrderListener, po, false); err != nil {
+		t.Fatalf("register order listener failed: %v", err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	objs, err := c.GetObjects(newTestCtx(), (*lifecycleListener)(nil))
+	if err != nil || len(objs) != 1 {
+		t.Fatalf("get listener failed: %v", err)
+	}
+	l := objs[0].Instance().(*lifecycleListener)
+	if l.initialized != 1 {
+		t.Fatalf("expected initialized event, got %d", l.initialized)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	c.EventBus().Publish(orderPlaced{id: 7})
+	objs, err = c.GetObjects(newTestCtx(), (*orderListener)(nil))
+	if err != nil || len(objs) != 1 {
+		t.Fatalf("get order listener failed: %v", err)
+	}
+	if ol := objs[0].Instance().(*orderListener); len(ol.got) != 1 || ol.got[0] != 7 {
+		t.Fatalf("custom event not delivered: %v", ol.got)
+	}
+	c.Shutdown()
+	c.Shutdown() // 重复关闭不应再次发布事件
+	if l.started != 1 || l.shutdown != 1 {
+		t.Fatalf("expected started/shutdown events, got %d/%d", l.started, l.shutdown)
+	}
+}
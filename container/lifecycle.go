@@ -73,6 +73,7 @@ func (p *lifecycleProcessor) start(ctx context.Context, factory ObjectFactory) e
 			l.Info("service started successfully", zap.String("service", obj.ID()))
 		}
 	}
+	factory.EventBus().Publish(ContainerStartedEvent{})
 	return nil
 }
 
@@ -43,6 +43,10 @@ type (
 		GetObjectsByName(ctx Context, name string) ([]Object, error)
 		// SetRealizationSelector sets the AutowiredSelector to be used for selecting Definitions during auto-wiring.
 		SetRealizationSelector(selector RealizationSelector)
+		// EventBus returns the bus on which container events are published and listeners are subscribed.
+		EventBus() *EventBus
+		// NewChild creates a child ObjectFactory whose lookups fall back to this factory.
+		NewChild() ObjectFactory
 		// Destroy cleans up resources and finalizes the ObjectFactory, returning an error if the operation fails.
 		Destroy()
 	}
@@ -55,23 +59,43 @@ func (fn RealizationSelectFunc) Select(defs []*object.Definition) *object.Defini
 
 // CoreObjectFactory is a factory for creating core objects, equipped with definition filters to
 // customize object creation.
+// A child factory, created with NewChild, builds only the definitions registered in its own registry;
+// objects of definitions inherited from the parent are obtained from the parent factory.
 type CoreObjectFactory struct {
 	object.DefinitionRegistry
+	parent   *CoreObjectFactory
 	once     *sync.Once
 	mutex    *sync.RWMutex
 	selector RealizationSelector
 	objs     map[string]Object
+	bus      *EventBus
 }
 
 // NewCoreObjectFactory creates a new instance of CoreObjectFactory with a namespace filter for the core namespace.
 func NewCoreObjectFactory() *CoreObjectFactory {
-	return &CoreObjectFactory{
+	c := &CoreObjectFactory{
 		DefinitionRegistry: object.NewDefinitionRegistry(),
 		once:               &sync.Once{},
 		mutex:              &sync.RWMutex{},
 		selector:           realizationSelectFunc,
 		objs:               map[string]Object{},
+		bus:                NewEventBus(),
 	}
+	c.SetProviderResolver(c.provide)
+	return c
+}
+
+// NewChild creates a child factory with its own registry, objects and EventBus. Definitions registered in the
+// child may add to or override the parent's; lookups of names the child does not define are served by the parent.
+func (c *CoreObjectFactory) NewChild() ObjectFactory {
+	child := NewCoreObjectFactory()
+	child.DefinitionRegistry = object.NewChildDefinitionRegistry(c.DefinitionRegistry)
+	child.SetProviderResolver(child.provide)
+	child.parent = c
+	c.mutex.RLock()
+	child.selector = c.selector
+	c.mutex.RUnlock()
+	return child
 }
 
 // SetRealizationSelector sets the autowiring selector for choosing a definition when multiple are available.
@@ -81,9 +105,19 @@ func (c *CoreObjectFactory) SetRealizationSelector(selector RealizationSelector)
 	c.selector = selector
 }
 
+// EventBus returns the bus on which container events are published and listeners are subscribed.
+func (c *CoreObjectFactory) EventBus() *EventBus {
+	return c.bus
+}
+
 // Init initializes the CoreObjectFactory and its singleton objects, returning an error if any occurs.
+// Singletons implementing Listener are subscribed to the EventBus, and a ContainerInitializedEvent
+// is published once every singleton has been created.
 func (c *CoreObjectFactory) Init() error {
-	var err error
+	var (
+		err         error
+		initialized bool
+	)
 	c.once.Do(func() {
 		c.mutex.Lock()
 		defer c.mutex.Unlock()
@@ -108,13 +142,22 @@ func (c *CoreObjectFactory) Init() error {
 				l.Debug("object initialized", zap.String("definition", def.String()))
 			}
 			c.objs[def.ID()] = obj
+			if c.bus.subscribeObject(obj) {
+				l.Debug("listener subscribed", zap.String("definition", def.String()))
+			}
 		}
+		initialized = true
 	})
+	if initialized {
+		c.bus.Publish(ContainerInitializedEvent{})
+	}
 	return err
 }
 
 // Destroy cleans up all created objects by calling their Destroy method, ensuring proper resource release.
+// Pending asynchronous event deliveries are awaited first so listeners never observe destroyed objects.
 func (c *CoreObjectFactory) Destroy() {
+	c.bus.Wait()
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	for _, obj := range c.objs {
@@ -154,6 +197,13 @@ func (c *CoreObjectFactory) getObjects(defs []*object.Definition, getCtx map[str
 			obj Object
 			err error
 		)
+		if c.inherited(def) {
+			if obj, err = c.parent.getObject(def); err != nil {
+				return nil, err
+			}
+			objs = append(objs, obj)
+			continue
+		}
 		if def.IsSingleton() {
 			if v, ok := c.objs[def.ID()]; ok {
 				obj = v
@@ -175,6 +225,36 @@ func (c *CoreObjectFactory) getObjects(defs []*object.Definition, getCtx map[str
 	return objs, nil
 }
 
+// getObject returns the object of a single definition, creating it if needed.
+func (c *CoreObjectFactory) getObject(def *object.Definition) (Object, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	objs, err := c.getObjects([]*object.Definition{def}, map[string]Object{})
+	if err != nil {
+		return nil, err
+	}
+	return objs[0], nil
+}
+
+// provide returns the object of the definition selected for name, created as its scope requires. It resolves
+// the providers injected into the definitions of this factory.
+func (c *CoreObjectFactory) provide(name string) (reflect.Value, error) {
+	def, err := c.getAutowiredDefinition(name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	obj, err := c.getObject(def)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return obj.Value(), nil
+}
+
+// inherited returns true if the definition comes from a parent registry and must be resolved by the parent factory.
+func (c *CoreObjectFactory) inherited(def *object.Definition) bool {
+	return c.parent != nil && !c.Contains(def)
+}
+
 // NewObject creates a new object based on the provided definition and context, handling dependencies.
 func (c *CoreObjectFactory) newObject(def *object.Definition, objs map[string]Object) (Object, error) {
 	if def.Factory().Argn() == 0 {
@@ -214,6 +294,13 @@ func (c *CoreObjectFactory) buildObject(def *object.Definition,
 		if err != nil {
 			return nil, err
 		}
+		if c.inherited(def) {
+			if obj, err = c.parent.getObject(def); err != nil {
+				return nil, err
+			}
+			objs[name] = obj
+			continue
+		}
 		if def.IsSingleton() {
 			obj, _ = c.objs[def.ID()]
 		}
@@ -245,6 +332,10 @@ func (c *CoreObjectFactory) getDependencies(def *object.Definition) ([]string, e
 		if err != nil {
 			return nil, err
 		}
+		if c.inherited(def) {
+			dag.AddNode(node)
+			continue
+		}
 		deps := def.DependsOn()
 		dag.AddNode(node, deps...)
 		queue = append(queue, deps...)
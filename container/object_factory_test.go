@@ -722,4 +722,65 @@ func TestObjectFactory_SetRealizationSelector_RecoverFromNil(t *testing.T) {
 	}
 }
 
+// ---------- Provider 注入 ----------
+type providerHolder struct {
+	next  object.Provider[*compC]
+	chain func() *compB
+}
+
+func newProviderHolder(next object.Provider[*compC], chain func() *compB) *providerHolder {
+	return &providerHolder{next: next, chain: chain}
+}
+
+// singleton 持有 prototype 的 provider：不算作用域违规，每次调用都得到新的 prototype
+func TestObjectFactory_Provider_FreshPrototype(t *testing.T) {
+	f := NewCoreObjectFactory()
+	f.SetScopePolicy(object.ScopePolicyError)
+	pc := object.NewProperty()
+	pc.Scope = object.Prototype
+	addAutowired(pc)
+	_, _ = f.RegisterFactory(newCompC, pc, false)
+	pb := object.NewProperty()
+	pb.Scope = object.Prototype
+	addAutowired(pb)
+	_, _ = f.RegisterFactory(newCompB, pb, false)
+	ph := object.NewProperty()
+	addAutowired(ph)
+	def, err := f.RegisterFactory(newProviderHolder, ph, false)
+	if err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if len(def.DependsOn()) != 0 || len(def.Providers()) != 2 {
+		t.Fatalf("providers should not be dependencies, got %v %v", def.DependsOn(), def.Providers())
+	}
+	if err := f.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	objs, err := f.GetObjects(newTestCtx(), (*providerHolder)(nil))
+	if err != nil || len(objs) != 1 {
+		t.Fatalf("get holder failed: %v", err)
+	}
+	holder := objs[0].Instance().(*providerHolder)
+	c1, c2 := holder.next(), holder.next()
+	if c1 == nil || c2 == nil || c1 == c2 || !c1.inited {
+		t.Fatalf("expected distinct initialized prototypes, got %p %p", c1, c2)
+	}
+	b1, b2 := holder.chain(), holder.chain()
+	if b1 == b2 || b1.c == nil || b1.c == b2.c {
+		t.Fatalf("expected fresh prototypes with fresh dependencies, got %+v %+v", b1, b2)
+	}
+}
+
+// provider 依赖缺失时 Init 报错
+func TestObjectFactory_Provider_Missing(t *testing.T) {
+	f := NewCoreObjectFactory()
+	ph := object.NewProperty()
+	addAutowired(ph)
+	_, _ = f.RegisterFactory(newProviderHolder, ph, false)
+	err := f.Init()
+	if err == nil || !strings.Contains(err.Error(), "definition not found") {
+		t.Fatalf("expected missing provider dependency error, got %v", err)
+	}
+}
+
 // ---------- 结束 ----------
@@ -2,6 +2,15 @@ module vortice
 
 go 1.23
 
-require go.uber.org/zap v1.27.0
+require (
+	github.com/BurntSushi/toml v1.4.0
+	go.uber.org/zap v1.27.0
+	golang.org/x/tools v0.28.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
-require go.uber.org/multierr v1.10.0 // indirect
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/mod v0.22.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+)
@@ -0,0 +1,315 @@
+// Package lint provides a go/analysis analyzer reporting vortice registration mistakes at vet time
+// rather than at DefaultDefRegistry.Init.
+//
+// It recognises calls to vortice.Register0..6, vortice.Get, vortice.GetElem and business.RegisterExt0..6
+// and reports:
+//   - factories whose output is not a struct, an interface or a pointer to a struct;
+//   - factory arguments of any other type;
+//   - factories that are unexported functions, when the -exported flag is set;
+//   - types registered more than once with vortice.Register*, which only allows unique definitions,
+//     including registrations in different packages, unless the later registration passes WithOverride;
+//     registrations passing WithProfile, WithCondition, WithOnMissing or WithOnPresent are alternatives
+//     selected at Init and never reported;
+//   - Get and GetElem calls in package main for types nobody registers in the program.
+//
+// Use it through cmd/vortice-vet:
+//
+//	go vet -vettool=$(which vortice-vet) ./...
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"regexp"
+	"slices"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	// vorticePkgPath is the import path of the package declaring Register0..6, Get and GetElem.
+	vorticePkgPath = "vortice"
+	// businessPkgPath is the import path of the package declaring RegisterExt0..6.
+	businessPkgPath = "vortice/business"
+)
+
+var (
+	// uniqueRegister matches the registration functions that require a unique definition name.
+	uniqueRegister = regexp.MustCompile(`^Register[0-6]$`)
+	// extRegister matches the extension registration functions, which allow several definitions per name.
+	extRegister = regexp.MustCompile(`^RegisterExt[0-6]$`)
+	// conditionOptions are the options making a registration an alternative selected at Init, which may
+	// share its name with other registrations.
+	conditionOptions = []string{"WithProfile", "WithCondition", "WithOnMissing", "WithOnPresent"}
+)
+
+// Analyzer reports vortice registration mistakes.
+var Analyzer = &analysis.Analyzer{
+	Name:      "vortice",
+	Doc:       "report vortice registration mistakes: invalid factory types, duplicate registrations and lookups of unregistered types",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(Registrations)},
+}
+
+// requireExported reports factories that are unexported functions. The runtime accepts them, since a
+// function value obtained through reflect.ValueOf always passes CanInterface, so the check is opt-in
+// for code bases that want every factory to be part of the package API.
+var requireExported bool
+
+func init() {
+	Analyzer.Flags.BoolVar(&requireExported, "exported", false, "report factories that are unexported functions")
+}
+
+// Registrations is the package fact listing every definition name registered by a package and its
+// transitive imports, mapped to the positions of the registering calls.
+type Registrations struct {
+	// Unique holds the names registered with vortice.Register*.
+	Unique map[string][]string
+	// Conditional holds the names registered with vortice.Register* and a profile or condition option.
+	Conditional map[string][]string
+	// Ext holds the names registered with business.RegisterExt*.
+	Ext map[string][]string
+}
+
+// AFact marks Registrations as an analysis.Fact.
+func (*Registrations) AFact() {}
+
+// String returns a stable description of the fact, used by analysistest.
+func (r *Registrations) String() string {
+	return fmt.Sprintf("registrations(%d unique, %d conditional, %d ext)", len(r.Unique), len(r.Conditional), len(r.Ext))
+}
+
+// call is a recognised vortice call.
+type call struct {
+	expr *ast.CallExpr
+	fn   *types.Func
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	regs := &Registrations{Unique: map[string][]string{}, Conditional: map[string][]string{}, Ext: map[string][]string{}}
+	mergeImports(pass, regs)
+
+	var calls []call
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		expr := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, expr).(*types.Func)
+		if !ok || fn.Pkg() == nil {
+			return
+		}
+		switch path := fn.Pkg().Path(); {
+		case path == vorticePkgPath && uniqueRegister.MatchString(fn.Name()),
+			path == vorticePkgPath && (fn.Name() == "Get" || fn.Name() == "GetElem"),
+			path == businessPkgPath && extRegister.MatchString(fn.Name()):
+			calls = append(calls, call{expr: expr, fn: fn})
+		}
+	})
+
+	var lookups []call
+	for _, c := range calls {
+		switch {
+		case uniqueRegister.MatchString(c.fn.Name()):
+			name, ok := checkFactory(pass, c)
+			if !ok {
+				continue
+			}
+			site := pass.Fset.Position(c.expr.Pos()).String()
+			if hasOption(pass, c.expr, conditionOptions...) {
+				regs.Conditional[name] = append(regs.Conditional[name], site)
+				continue
+			}
+			if hasOption(pass, c.expr, "WithOverride") {
+				regs.Unique[name] = []string{site}
+				continue
+			}
+			if prev, ok := regs.Unique[name]; ok {
+				pass.Reportf(c.expr.Pos(), "duplicate registration of %s: already registered at %s", name, prev[0])
+			}
+			regs.Unique[name] = append(regs.Unique[name], site)
+		case extRegister.MatchString(c.fn.Name()):
+			if name, ok := checkFactory(pass, c); ok {
+				regs.Ext[name] = append(regs.Ext[name], pass.Fset.Position(c.expr.Pos()).String())
+			}
+		default:
+			lookups = append(lookups, c)
+		}
+	}
+	if pass.Pkg.Name() == "main" {
+		checkLookups(pass, regs, lookups)
+	}
+	pass.ExportPackageFact(regs)
+	return nil, nil
+}
+
+// mergeImports merges the Registrations facts of the directly imported packages into regs, reporting
+// unique names registered by two imports that do not know about each other at the import spec.
+func mergeImports(pass *analysis.Pass, regs *Registrations) {
+	for _, file := range pass.Files {
+		for _, spec := range file.Imports {
+			obj, ok := pass.TypesInfo.Implicits[spec].(*types.PkgName)
+			if !ok {
+				if obj, ok = pass.TypesInfo.Defs[spec.Name].(*types.PkgName); !ok {
+					continue
+				}
+			}
+			fact := new(Registrations)
+			if !pass.ImportPackageFact(obj.Imported(), fact) {
+				continue
+			}
+			for name, sites := range fact.Unique {
+				prev := regs.Unique[name]
+				merged := union(prev, sites)
+				if len(prev) > 0 && len(merged) > len(prev) && len(merged) > len(sites) {
+					pass.Reportf(spec.Pos(), "duplicate registration of %s: registered at %s and %s",
+						name, prev[0], sites[0])
+				}
+				regs.Unique[name] = merged
+			}
+			for name, sites := range fact.Conditional {
+				regs.Conditional[name] = union(regs.Conditional[name], sites)
+			}
+			for name, sites := range fact.Ext {
+				regs.Ext[name] = union(regs.Ext[name], sites)
+			}
+		}
+	}
+}
+
+// checkFactory validates the factory passed to a registration call and returns the definition name it registers.
+func checkFactory(pass *analysis.Pass, c call) (string, bool) {
+	if len(c.expr.Args) == 0 {
+		return "", false
+	}
+	arg := c.expr.Args[0]
+	sig, ok := pass.TypesInfo.TypeOf(arg).Underlying().(*types.Signature)
+	if !ok {
+		return "", false
+	}
+	if fn := funcOf(pass.TypesInfo, arg); requireExported && fn != nil && !fn.Exported() {
+		pass.Reportf(arg.Pos(), "factory %s is not exported", fn.Name())
+	}
+	valid := true
+	for i := 0; i < sig.Params().Len(); i++ {
+		if typ := sig.Params().At(i).Type(); !validType(typ) {
+			pass.Reportf(arg.Pos(), "invalid argument type: %s (must be a struct, an interface or a pointer to a struct)",
+				types.TypeString(typ, nil))
+			valid = false
+		}
+	}
+	if sig.Results().Len() != 1 {
+		pass.Reportf(arg.Pos(), "factory must have exactly one return value")
+		return "", false
+	}
+	out := sig.Results().At(0).Type()
+	if !validType(out) {
+		pass.Reportf(arg.Pos(), "invalid output type: %s (must be a struct, an interface or a pointer to a struct)",
+			types.TypeString(out, nil))
+		return "", false
+	}
+	return definitionName(out), valid
+}
+
+// checkLookups reports Get and GetElem calls for types registered neither by the package nor by its imports.
+func checkLookups(pass *analysis.Pass, regs *Registrations, lookups []call) {
+	for _, c := range lookups {
+		if len(c.expr.Args) < 2 {
+			continue
+		}
+		typ := pass.TypesInfo.TypeOf(c.expr.Args[1])
+		if c.fn.Name() == "GetElem" {
+			ptr, ok := typ.(*types.Pointer)
+			if !ok {
+				continue
+			}
+			typ = ptr.Elem()
+		}
+		if !validType(typ) {
+			continue
+		}
+		name := definitionName(typ)
+		if len(regs.Unique[name]) == 0 && len(regs.Conditional[name]) == 0 && len(regs.Ext[name]) == 0 {
+			pass.Reportf(c.expr.Pos(), "%s of %s, which is never registered", c.fn.Name(), name)
+		}
+	}
+}
+
+// hasOption returns true if the registration passes one of the named vortice options, such as WithOverride,
+// which makes duplicates intentional.
+func hasOption(pass *analysis.Pass, expr *ast.CallExpr, names ...string) bool {
+	for _, arg := range expr.Args[1:] {
+		opt, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		fn, ok := typeutil.Callee(pass.TypesInfo, opt).(*types.Func)
+		if ok && fn.Pkg() != nil && fn.Pkg().Path() == vorticePkgPath && slices.Contains(names, fn.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// funcOf resolves the factory expression to a declared function, or nil for closures and variables.
+func funcOf(info *types.Info, expr ast.Expr) *types.Func {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		fn, _ := info.Uses[e].(*types.Func)
+		return fn
+	case *ast.SelectorExpr:
+		fn, _ := info.Uses[e.Sel].(*types.Func)
+		return fn
+	}
+	return nil
+}
+
+// validType mirrors the runtime check of factory arguments and outputs: a struct, an interface or a pointer to a struct.
+func validType(typ types.Type) bool {
+	switch t := typ.Underlying().(type) {
+	case *types.Struct, *types.Interface:
+		return true
+	case *types.Pointer:
+		_, ok := t.Elem().Underlying().(*types.Struct)
+		return ok
+	}
+	return false
+}
+
+// definitionName mirrors object.GenerateDefinitionName for a static type.
+func definitionName(typ types.Type) string {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		name := types.TypeString(typ, nil)
+		if _, ok := typ.Underlying().(*types.Struct); ok {
+			name = "*" + name
+		}
+		return "." + name
+	}
+	name := named.Obj().Name()
+	if _, ok := named.Underlying().(*types.Struct); ok {
+		name = "*" + name
+	}
+	return named.Obj().Pkg().Path() + "." + name
+}
+
+// union returns the sorted union of two site lists.
+func union(a, b []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,19 @@
+package lint
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// ---------- 工厂类型、重复注册（条件注册除外）与未注册类型的查找 ----------
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a", "b", "app", "dup", "cond")
+}
+
+// ---------- -exported 开启时报告未导出的工厂函数 ----------
+func TestAnalyzer_RequireExported(t *testing.T) {
+	requireExported = true
+	defer func() { requireExported = false }()
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "c")
+}
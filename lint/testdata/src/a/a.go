@@ -0,0 +1,33 @@
+package a // want package:"registrations\\(1 unique, 0 conditional, 1 ext\\)"
+
+import (
+	"vortice"
+	"vortice/business"
+)
+
+type Repo struct{}
+
+type Greeter interface{ Greet() string }
+
+type Counter int
+
+func NewRepo() *Repo { return &Repo{} }
+
+func newRepoAgain() *Repo { return &Repo{} }
+
+func NewCounter() Counter { return 0 }
+
+func NewFromInt(n int) *Repo { return &Repo{} }
+
+func NewGreeter(r *Repo) Greeter { return nil }
+
+func init() {
+	vortice.Register0(NewRepo)
+	vortice.Register0(newRepoAgain)               // want `duplicate registration of a.\*Repo: already registered at .*a.go:25:2`
+	vortice.Register0(NewCounter)                 // want `invalid output type: a.Counter`
+	vortice.Register1(NewFromInt)                 // want `invalid argument type: int`
+	vortice.Register0(func() *int { return nil }) // want `invalid output type: \*int`
+	business.RegisterExt1(NewGreeter)
+	business.RegisterExt1(NewGreeter)
+	vortice.Register0(newRepoAgain, vortice.WithOverride())
+}
@@ -0,0 +1,21 @@
+package main // want package:"registrations\\(2 unique, 0 conditional, 1 ext\\)"
+
+import (
+	"context"
+
+	"a"
+	"c"
+	"vortice"
+)
+
+type Missing struct{}
+
+type Unknown interface{ Do() }
+
+func main() {
+	ctx := context.Background()
+	_ = vortice.Get(ctx, (*c.Service)(nil))
+	_ = vortice.GetElem(ctx, (*a.Greeter)(nil))
+	_ = vortice.Get(ctx, (*Missing)(nil))     // want `Get of app.\*Missing, which is never registered`
+	_ = vortice.GetElem(ctx, (*Unknown)(nil)) // want `GetElem of app.Unknown, which is never registered`
+}
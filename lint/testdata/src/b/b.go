@@ -0,0 +1,12 @@
+package b // want package:"registrations\\(1 unique, 0 conditional, 1 ext\\)"
+
+import (
+	"a"
+	"vortice"
+)
+
+func newRepo() *a.Repo { return &a.Repo{} }
+
+func init() {
+	vortice.Register0(newRepo) // want `duplicate registration of a.\*Repo`
+}
@@ -0,0 +1,11 @@
+package c // want package:"registrations\\(1 unique, 0 conditional, 0 ext\\)"
+
+import "vortice"
+
+type Service struct{}
+
+func newService() *Service { return &Service{} }
+
+func init() {
+	vortice.Register0(newService) // want `factory newService is not exported`
+}
@@ -0,0 +1,3 @@
+package common
+
+type T struct{}
@@ -0,0 +1,23 @@
+package cond // want package:"registrations\\(1 unique, 2 conditional, 0 ext\\)"
+
+import "vortice"
+
+type DB struct{}
+
+type Cache struct{}
+
+func newDevDB() *DB       { return &DB{} }
+func newProdDB() *DB      { return &DB{} }
+func newCache() *Cache    { return &Cache{} }
+func newLRU() *Cache      { return &Cache{} }
+func newRedis() *Cache    { return &Cache{} }
+func newMemcache() *Cache { return &Cache{} }
+
+func init() {
+	vortice.Register0(newDevDB, vortice.WithProfile("dev"))
+	vortice.Register0(newProdDB, vortice.WithProfile("prod"))
+	vortice.Register0(newCache, vortice.WithOnMissing[*Cache]())
+	vortice.Register0(newLRU, vortice.WithCondition(func(env vortice.Env) bool { return env.ProfileActive("lru") }))
+	vortice.Register0(newRedis)
+	vortice.Register0(newMemcache) // want `duplicate registration of cond.\*Cache: already registered at .*cond.go:21:2`
+}
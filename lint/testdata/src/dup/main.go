@@ -0,0 +1,8 @@
+package main // want package:"registrations\\(1 unique, 0 conditional, 0 ext\\)"
+
+import (
+	_ "s1"
+	_ "s2" // want `duplicate registration of common.\*T: registered at .*s1.go:11:2 and .*s2.go:11:2`
+)
+
+func main() {}
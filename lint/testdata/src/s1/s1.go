@@ -0,0 +1,12 @@
+package s1
+
+import (
+	"common"
+	"vortice"
+)
+
+func newT() *common.T { return &common.T{} }
+
+func init() {
+	vortice.Register0(newT)
+}
@@ -0,0 +1,5 @@
+// Package business is a minimal stub of the vortice business API used by the analyzer tests.
+package business
+
+func RegisterExt0[T any](fn func() T, opts ...any)     {}
+func RegisterExt1[T, A any](fn func(A) T, opts ...any) {}
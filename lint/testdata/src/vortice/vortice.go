@@ -0,0 +1,22 @@
+// Package vortice is a minimal stub of the vortice API used by the analyzer tests.
+package vortice
+
+import "context"
+
+func Register0[T any](fn func() T, opts ...any)           {}
+func Register1[T, A any](fn func(A) T, opts ...any)       {}
+func Register2[T, A, B any](fn func(A, B) T, opts ...any) {}
+
+func Get[T any](ctx context.Context, typ T) T      { return typ }
+func GetElem[T any](ctx context.Context, typ *T) T { return *typ }
+
+type Option func()
+
+func WithOverride() Option { return nil }
+
+type Env interface{ ProfileActive(profile string) bool }
+
+func WithProfile(profiles ...string) Option      { return nil }
+func WithCondition(fn func(env Env) bool) Option { return nil }
+func WithOnMissing[T any]() Option               { return nil }
+func WithOnPresent[T any]() Option               { return nil }
@@ -0,0 +1,219 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"vortice/util"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// ProfilesEnvKey is the environment variable holding the comma-separated active profiles,
+	// used when no profiles are set on the registry.
+	ProfilesEnvKey = "VORTICE_PROFILES"
+)
+
+type (
+	// Env is the environment conditions are evaluated against during Init.
+	Env interface {
+		// Profiles returns the active profiles.
+		Profiles() []string
+		// ProfileActive returns true if the profile is active.
+		ProfileActive(profile string) bool
+		// Lookup returns the value of an environment variable.
+		Lookup(key string) (string, bool)
+		// Provides returns true if a selected definition other than the one being evaluated has the given name.
+		Provides(name string) bool
+	}
+	// Condition decides during Init whether a definition is selected. Conditions on other definitions,
+	// such as OnMissingCondition, are evaluated after all other conditions, in registration order.
+	Condition struct {
+		deferred bool
+		eval     func(env Env) (bool, string)
+	}
+	// Exclusion records a definition that was not selected during Init, and why.
+	Exclusion struct {
+		// Definition is the excluded definition.
+		Definition *Definition
+		// Reason describes the condition that was not met.
+		Reason string
+	}
+)
+
+// String returns a description of the exclusion including the factory location.
+func (e Exclusion) String() string {
+	return fmt.Sprintf("excluded: %s (at %s): %s", e.Definition.ID(), location(e.Definition), e.Reason)
+}
+
+// NewCondition creates a Condition from a predicate; desc is reported when the predicate returns false.
+func NewCondition(desc string, fn func(env Env) bool) Condition {
+	return Condition{eval: func(env Env) (bool, string) {
+		return fn(env), "condition not met: " + desc
+	}}
+}
+
+// ProfileCondition matches if any of the profiles is active. A profile prefixed with "!" matches if
+// that profile is not active.
+func ProfileCondition(profiles ...string) Condition {
+	return Condition{eval: func(env Env) (bool, string) {
+		for _, profile := range profiles {
+			if name, ok := strings.CutPrefix(profile, "!"); ok {
+				if !env.ProfileActive(name) {
+					return true, ""
+				}
+			} else if env.ProfileActive(profile) {
+				return true, ""
+			}
+		}
+		return false, fmt.Sprintf("profile %s not matched (active: %s)",
+			strings.Join(profiles, ","), strings.Join(env.Profiles(), ","))
+	}}
+}
+
+// OnMissingCondition matches if no other selected definition has the given name, letting a default back off
+// when an application registers its own implementation.
+func OnMissingCondition(name string) Condition {
+	return Condition{deferred: true, eval: func(env Env) (bool, string) {
+		return !env.Provides(name), "on missing " + name + ": already provided"
+	}}
+}
+
+// OnPresentCondition matches if another selected definition has the given name.
+func OnPresentCondition(name string) Condition {
+	return Condition{deferred: true, eval: func(env Env) (bool, string) {
+		return env.Provides(name), "on present " + name + ": not provided"
+	}}
+}
+
+// AddConditions appends conditions that must all match for the definition to be selected during Init.
+func (prop *Property) AddConditions(conds ...Condition) {
+	prop.conditions = append(prop.conditions, conds...)
+}
+
+// registryEnv is the Env of a DefaultDefRegistry while it evaluates the conditions of one definition.
+type registryEnv struct {
+	dr       *DefaultDefRegistry
+	profiles []string
+	self     *Definition
+	pending  map[*Definition]bool
+}
+
+// Profiles returns the active profiles.
+func (e *registryEnv) Profiles() []string {
+	return append([]string{}, e.profiles...)
+}
+
+// ProfileActive returns true if the profile is active.
+func (e *registryEnv) ProfileActive(profile string) bool {
+	return slices.Contains(e.profiles, profile)
+}
+
+// Lookup returns the value of an environment variable.
+func (e *registryEnv) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// Provides returns true if a selected definition other than the evaluated one, and not itself awaiting
+// evaluation, has the name. Definitions inherited from a parent registry count as selected.
+func (e *registryEnv) Provides(name string) bool {
+	defs, ok := e.dr.entries[name]
+	if !ok && e.dr.parent != nil {
+		return len(e.dr.parent.GetDefinitionsByName(name)) > 0
+	}
+	for _, def := range defs {
+		if def != e.self && !e.pending[def] {
+			return true
+		}
+	}
+	return false
+}
+
+// activeProfiles returns the profiles set on the registry, or those of the ProfilesEnvKey environment variable.
+func (dr *DefaultDefRegistry) activeProfiles() []string {
+	if dr.profiles != nil {
+		return dr.profiles
+	}
+	var profiles []string
+	for _, profile := range strings.Split(os.Getenv(ProfilesEnvKey), ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// evaluateConditions removes every definition whose conditions do not match and records it as excluded.
+// Definitions without deferred conditions are evaluated first; the others are then evaluated one by one
+// in registration order, each seeing the outcome of the previous ones.
+func (dr *DefaultDefRegistry) evaluateConditions() {
+	env := &registryEnv{dr: dr, profiles: dr.activeProfiles(), pending: map[*Definition]bool{}}
+	var deferred []*Definition
+	for _, def := range dr.GetDefinitions() {
+		if slices.ContainsFunc(def.conditions, func(c Condition) bool { return c.deferred }) {
+			env.pending[def] = true
+			deferred = append(deferred, def)
+		}
+	}
+	var excluded []Exclusion
+	for _, def := range dr.GetDefinitions() {
+		if env.pending[def] {
+			continue
+		}
+		env.self = def
+		if ok, reason := matches(env, def); !ok {
+			excluded = append(excluded, Exclusion{Definition: def, Reason: reason})
+		}
+	}
+	for _, e := range excluded {
+		dr.remove(e.Definition)
+	}
+	for _, def := range deferred {
+		env.self = def
+		delete(env.pending, def)
+		if ok, reason := matches(env, def); !ok {
+			dr.remove(def)
+			excluded = append(excluded, Exclusion{Definition: def, Reason: reason})
+		}
+	}
+	for _, e := range excluded {
+		util.Logger().Info("definition excluded", zap.String("definition", e.Definition.ID()),
+			zap.String("location", location(e.Definition)), zap.String("reason", e.Reason))
+	}
+	dr.excluded = append(dr.excluded, excluded...)
+}
+
+// checkUnique returns an error for every name that a unique definition shares with other definitions
+// still selected after the conditions have been evaluated.
+func (dr *DefaultDefRegistry) checkUnique() error {
+	var errs []error
+	seen := map[string]bool{}
+	for _, def := range dr.GetDefinitions() {
+		if !def.unique || seen[def.Name()] || len(dr.entries[def.Name()]) < 2 {
+			continue
+		}
+		seen[def.Name()] = true
+		errs = append(errs, fmt.Errorf("object type %s does not allow duplicate definition: %d definitions selected",
+			def.Name(), len(dr.entries[def.Name()])))
+	}
+	return errors.Join(errs...)
+}
+
+// isConditional returns true if any of the definitions has conditions.
+func isConditional(defs ...*Definition) bool {
+	return slices.ContainsFunc(defs, func(def *Definition) bool { return len(def.conditions) > 0 })
+}
+
+// matches evaluates all conditions of the definition, returning the reason of the first one not met.
+func matches(env Env, def *Definition) (bool, string) {
+	for _, cond := range def.conditions {
+		if ok, reason := cond.eval(env); !ok {
+			return false, reason
+		}
+	}
+	return true, ""
+}
@@ -0,0 +1,136 @@
+package object
+
+import (
+	"strings"
+	"testing"
+)
+
+func conditional(name, factory string, conds ...Condition) *Definition {
+	def := makeTestDefinition(name, factory, nil)
+	def.conditions = conds
+	return def
+}
+
+func TestConditions_Profiles(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	reg.SetProfiles("prod")
+	prod := conditional("store", "prodStore", ProfileCondition("prod"))
+	dev := conditional("store", "devStore", ProfileCondition("dev", "test"))
+	notProd := conditional("debug", "debugTool", ProfileCondition("!prod"))
+	for _, def := range []*Definition{prod, dev, notProd} {
+		if err := reg.register(def, false); err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+	}
+	if err := reg.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if defs := reg.GetDefinitionsByName("store"); len(defs) != 1 || defs[0] != prod {
+		t.Fatalf("only the prod store should be selected, got %v", defs)
+	}
+	excluded := reg.Excluded()
+	if len(excluded) != 2 || excluded[0].Definition != dev || excluded[1].Definition != notProd {
+		t.Fatalf("unexpected exclusions: %v", excluded)
+	}
+	if !strings.Contains(excluded[0].String(), "profile dev,test not matched (active: prod)") {
+		t.Fatalf("exclusion should carry the reason, got %s", excluded[0])
+	}
+	if report := reg.Validate(); len(report.Excluded) != 2 || !strings.Contains(report.String(), "excluded: devStore") {
+		t.Fatalf("report should list exclusions: %s", report)
+	}
+}
+
+func TestConditions_ProfilesFromEnv(t *testing.T) {
+	t.Setenv(ProfilesEnvKey, " staging , prod")
+	reg := NewDefinitionRegistry()
+	if got := reg.activeProfiles(); len(got) != 2 || got[0] != "staging" || got[1] != "prod" {
+		t.Fatalf("unexpected profiles %v", got)
+	}
+}
+
+func TestConditions_Func(t *testing.T) {
+	t.Setenv("FEATURE_X", "on")
+	reg := NewDefinitionRegistry()
+	on := conditional("x", "xOn", NewCondition("feature x", func(env Env) bool {
+		v, _ := env.Lookup("FEATURE_X")
+		return v == "on"
+	}))
+	off := conditional("y", "yOff", NewCondition("feature y", func(env Env) bool { return false }))
+	_ = reg.register(on, false)
+	_ = reg.register(off, false)
+	if err := reg.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if len(reg.GetDefinitions()) != 1 || reg.Excluded()[0].Reason != "condition not met: feature y" {
+		t.Fatalf("unexpected selection: %v / %v", reg.GetDefinitions(), reg.Excluded())
+	}
+}
+
+func TestConditions_OnMissingAndOnPresent(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	// 库提供的默认实现先注册，应用自己的实现后注册
+	fallback := conditional("cache", "defaultCache", OnMissingCondition("cache"))
+	custom := makeTestDefinition("cache", "appCache", nil)
+	metrics := conditional("metrics", "cacheMetrics", OnPresentCondition("cache"))
+	tracer := conditional("tracer", "tracerHook", OnPresentCondition("tracing"))
+	for _, def := range []*Definition{fallback, custom, metrics, tracer} {
+		if err := reg.register(def, false); err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+	}
+	if err := reg.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if defs := reg.GetDefinitionsByName("cache"); len(defs) != 1 || defs[0] != custom {
+		t.Fatalf("default should back off, got %v", defs)
+	}
+	if len(reg.GetDefinitionsByName("metrics")) != 1 || len(reg.GetDefinitionsByName("tracer")) != 0 {
+		t.Fatalf("on-present conditions evaluated incorrectly")
+	}
+	if len(reg.Excluded()) != 2 {
+		t.Fatalf("unexpected exclusions: %v", reg.Excluded())
+	}
+}
+
+func TestConditions_OnMissingFirstWins(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	a := conditional("cache", "cacheA", OnMissingCondition("cache"))
+	b := conditional("cache", "cacheB", OnMissingCondition("cache"))
+	_ = reg.register(a, false)
+	_ = reg.register(b, false)
+	if err := reg.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if defs := reg.GetDefinitionsByName("cache"); len(defs) != 1 || defs[0] != a {
+		t.Fatalf("first default in registration order should win, got %v", defs)
+	}
+}
+
+func TestConditions_MissingDependencyMentionsExclusion(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	reg.SetProfiles("dev")
+	dep := conditional("db", "prodDB", ProfileCondition("prod"))
+	user := makeTestDefinition("repo", "newRepo", nil)
+	user.dependsOn = []string{"db"}
+	_ = reg.register(dep, false)
+	_ = reg.register(user, false)
+	err := reg.Init()
+	if err == nil || !strings.Contains(err.Error(), "excluded: prodDB") {
+		t.Fatalf("missing dependency should mention the excluded definition, got %v", err)
+	}
+}
+
+func TestConditions_UniqueCheckedAfterSelection(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	reg.SetProfiles("prod", "dev")
+	if err := reg.register(conditional("store", "prodStore", ProfileCondition("prod")), true); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if err := reg.register(conditional("store", "devStore", ProfileCondition("dev")), true); err != nil {
+		t.Fatalf("conditional alternatives should be accepted at registration: %v", err)
+	}
+	err := reg.Init()
+	if err == nil || !strings.Contains(err.Error(), "does not allow duplicate definition: 2 definitions selected") {
+		t.Fatalf("two selected unique definitions should fail Init, got %v", err)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime"
+	"sort"
+	"sync/atomic"
 )
 
 type (
@@ -25,6 +28,9 @@ var (
 	ErrDefinitionOutput = errors.New("invalid definition output")
 	// ErrMissingRequiredField indicates a required field is missing in the definition.
 	ErrMissingRequiredField = errors.New("missing required field in definition")
+
+	// instanceSeq numbers the factories created by ParseInstance and ParseSupplier.
+	instanceSeq atomic.Uint64
 )
 
 // GenerateDefinitionName creates a unique name for a definition based on the provided namespace and argument type.
@@ -44,6 +50,13 @@ type Definition struct {
 	desc        string
 	lazyInit    bool
 	autoStartup bool
+	override    bool
+	conditions  []Condition
+	unique      bool
+	checks      []func() error
+	values      []ValueRequest
+	providers   []string
+	binding     *valueBinding
 	tags        []Tag // tags holds a list of string tags associated with the component definition.
 }
 
@@ -53,6 +66,46 @@ func ParseDefinition(fn any, prop *Property) (*Definition, error) {
 	return parser.Parse(prop)
 }
 
+// ParseInstance creates a Definition whose factory always returns the given instance as type typ, which must be
+// a valid output type the instance is assignable to. Every call yields a distinct factory ID, so the same type
+// can be provided by several instances; the factory location is that of the caller.
+func ParseInstance(typ reflect.Type, ins any, prop *Property) (*Definition, error) {
+	rv := reflect.ValueOf(ins)
+	if typ == nil || !rv.IsValid() || !rv.Type().AssignableTo(typ) {
+		return nil, errors.Join(ErrDefinitionInput, fmt.Errorf("instance %T is not assignable to %v", ins, typ))
+	}
+	return parseSupplier(typ, "instance", func() any { return ins }, prop)
+}
+
+// ParseSupplier creates a Definition whose factory returns the current result of supplier as type typ, such as
+// the latest value of a reloadable configuration; with the Prototype scope every lookup calls supplier again.
+// A result not assignable to typ yields the zero value. Factory IDs and location follow ParseInstance.
+func ParseSupplier(typ reflect.Type, supplier func() any, prop *Property) (*Definition, error) {
+	if typ == nil || supplier == nil {
+		return nil, errors.Join(ErrDefinitionInput, fmt.Errorf("supplier of %v cannot be nil", typ))
+	}
+	return parseSupplier(typ, "supplier", supplier, prop)
+}
+
+// parseSupplier creates the Definition of ParseInstance and ParseSupplier, located at the caller of its caller.
+func parseSupplier(typ reflect.Type, kind string, supplier func() any, prop *Property) (*Definition, error) {
+	fn := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{typ}, false), func([]reflect.Value) []reflect.Value {
+		out := reflect.New(typ).Elem()
+		if rv := reflect.ValueOf(supplier()); rv.IsValid() && rv.Type().AssignableTo(typ) {
+			out.Set(rv)
+		}
+		return []reflect.Value{out}
+	})
+	def, err := NewParser(fn.Interface()).Parse(prop)
+	if err != nil {
+		return nil, err
+	}
+	_, file, line, _ := runtime.Caller(2)
+	def.factory.name = fmt.Sprintf("%s(%s)#%d", kind, def.name, instanceSeq.Add(1))
+	def.factory.file, def.factory.line = file, line
+	return def, nil
+}
+
 // IsValid checks if the Definition is valid, ensuring name, type, factory, dependsOn, and methods are set, and tags are not.
 func (d *Definition) IsValid() bool {
 	return d.name != "" && d.typ != nil && d.factory != nil &&
@@ -115,6 +168,11 @@ func (d *Definition) AutoStartup() bool {
 	return d.autoStartup
 }
 
+// Override returns whether the definition replaces existing definitions of the same name when registered.
+func (d *Definition) Override() bool {
+	return d.override
+}
+
 // Tags returns a copy of the tags for the component definition.
 // Always returns a non-nil slice (at least empty).
 func (d *Definition) Tags() []Tag {
@@ -145,7 +203,10 @@ type Property struct {
 	Desc        string
 	LazyInit    bool
 	AutoStartup bool
+	Override    bool
 	tags        map[string]Tag
+	conditions  []Condition
+	checks      []func() error
 }
 
 // NewProperty creates a new Property instance with default values.
@@ -169,15 +230,22 @@ func (prop *Property) SetTags(tags ...Tag) {
 	}
 }
 
-// GetTags returns a copy of the tags associated with the property.
+// GetTags returns a copy of the tags associated with the property, sorted by key.
 func (prop *Property) GetTags() []Tag {
 	tags := make([]Tag, 0, len(prop.tags))
 	for _, tag := range prop.tags {
 		tags = append(tags, tag)
 	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].key < tags[j].key })
 	return tags
 }
 
+// AddChecks appends checks run by Validate, and therefore at Init, before any object is created.
+// A check returning an error fails Init with a message naming the definition.
+func (prop *Property) AddChecks(checks ...func() error) {
+	prop.checks = append(prop.checks, checks...)
+}
+
 // Tag represents a key-value pair used for tagging or labeling.
 type Tag struct {
 	key string
@@ -215,14 +283,17 @@ type Option func(prop *Property)
 // Parser is a struct used for parsing and validating function definitions,
 // ensuring they meet certain criteria.
 type Parser struct {
-	fn   any
-	rv   reflect.Value
-	rt   reflect.Type
-	rk   reflect.Kind
-	argv []reflect.Value
-	argn int
-	deps []string
-	obj  reflect.Type
+	fn        any
+	rv        reflect.Value
+	rt        reflect.Type
+	rk        reflect.Kind
+	argv      []reflect.Value
+	argn      int
+	deps      []string
+	obj       reflect.Type
+	params    []inParam
+	values    []ValueRequest
+	providers []string
 }
 
 // NewParser initializes a new parser instance for a given function,
@@ -253,15 +324,22 @@ func (p *Parser) Parse(prop *Property) (*Definition, error) {
 
 // newDefinition creates and returns a new Definition based on the parsed function and properties.
 func (p *Parser) newDefinition(prop *Property) *Definition {
+	factory, binding := p.newFactory()
 	return &Definition{
 		name:        generateReflectionName(p.obj),
 		typ:         p.rt,
-		factory:     NewFactory(p.rv, p.argv, p.argn),
+		factory:     factory,
 		dependsOn:   p.deps,
 		methods:     newMethods(p.obj),
 		scope:       prop.Scope,
 		lazyInit:    prop.LazyInit,
 		autoStartup: prop.AutoStartup,
+		override:    prop.Override,
+		conditions:  append([]Condition{}, prop.conditions...),
+		checks:      append([]func() error{}, prop.checks...),
+		values:      p.values,
+		providers:   p.providers,
+		binding:     binding,
 		tags:        prop.GetTags(),
 	}
 }
@@ -289,16 +367,32 @@ func (p *Parser) checkInputAndSet() error {
 	p.rk = rv.Kind()
 	for i := 0; i < p.rt.NumIn(); i++ {
 		argType := p.rt.In(i)
+		if isIn(argType) {
+			if err := p.addIn(argType); err != nil {
+				return err
+			}
+			continue
+		}
+		if elem, ok := p.providedType(argType); ok {
+			p.addProvider(argType, elem)
+			continue
+		}
 		if err := p.checkArgType(argType); err != nil {
 			return err
 		}
-		p.argn = p.rt.NumIn()
-		p.argv = append(p.argv, reflect.ValueOf(argType))
-		p.deps = append(p.deps, p.generateDefinitionName(argType))
+		p.addArg(argType)
+		p.params = append(p.params, inParam{typ: argType})
 	}
 	return nil
 }
 
+// addArg adds a dependency on the definition of the argument type.
+func (p *Parser) addArg(argType reflect.Type) {
+	p.argv = append(p.argv, reflect.ValueOf(argType))
+	p.deps = append(p.deps, p.generateDefinitionName(argType))
+	p.argn = len(p.argv)
+}
+
 // checkOutputAndSet verifies the output of the function,
 // ensuring it has exactly one return value and sets the object type.
 func (p *Parser) checkOutputAndSet() error {
@@ -3,7 +3,9 @@ package object
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 	"reflect"
+	"slices"
 	"sync/atomic"
 
 	"vortice/util"
@@ -26,12 +28,41 @@ type (
 		// RegisterFactory registers a factory function with the given property and returns a new Definition,
 		// or an error if registration fails.
 		RegisterFactory(fn any, prop *Property, unique bool) (*Definition, error)
+		// RegisterDefinition registers an already parsed Definition, such as one created by ParseInstance.
+		RegisterDefinition(def *Definition, unique bool) error
 		// GetDefinitions returns a list of all Definitions, optionally filtered by the provided DefinitionFilter functions.
 		GetDefinitions(filters ...DefinitionFilter) []*Definition
 		// GetDefinitionsByName retrieves a list of Definitions by name, optionally filtered by the provided DefinitionFilter functions.
 		GetDefinitionsByName(name string, filters ...DefinitionFilter) []*Definition
 		// GetDefinitionsByType retrieves a list of Definitions matching the given type, optionally filtered by provided filters.
 		GetDefinitionsByType(typ any, filters ...DefinitionFilter) ([]*Definition, error)
+		// Validate checks all registered definitions and returns a report of every problem found.
+		Validate() *ValidationReport
+		// SetScopePolicy sets how scope violations found during Init are treated.
+		SetScopePolicy(policy ScopePolicy)
+		// SetRandomOrder makes Init shuffle the order of independent definitions using the given seed.
+		SetRandomOrder(seed int64)
+		// Graph returns a snapshot of the component graph formed by all definitions and their dependencies.
+		Graph() *Graph
+		// Contains returns true if the definition is registered in this registry itself rather than inherited from a parent.
+		Contains(def *Definition) bool
+		// Replace removes every definition sharing the definition's name and registers the definition in their place.
+		Replace(def *Definition) error
+		// SetAllowOverriding sets whether a registration colliding with an existing definition replaces it
+		// instead of failing.
+		SetAllowOverriding(allow bool)
+		// SetProfiles sets the active profiles conditions are evaluated against during Init.
+		SetProfiles(profiles ...string)
+		// Excluded returns the definitions whose conditions did not match during Init, with the reasons.
+		Excluded() []Exclusion
+		// SetValue sets a named value injected into In struct fields; value sources take precedence.
+		SetValue(key string, value any)
+		// AddValueSource adds a source of named values taking precedence over those set before.
+		AddValueSource(src ValueSource)
+		// LookupValue returns a named value, falling back to the parent registry.
+		LookupValue(key string) (any, bool)
+		// SetProviderResolver sets the resolver of the providers injected into the registered definitions.
+		SetProviderResolver(resolver ProviderResolver)
 	}
 )
 
@@ -61,11 +92,23 @@ func TagFilter(tags ...Tag) DefinitionFilter {
 
 // DefaultDefRegistry manages a collection of component definitions and their associated factories,
 // supporting read-only state.
+// All queries return definitions in a stable order: registration order before Init,
+// dependency-first (ties broken by registration order) after Init.
+// A child registry, created with NewChildDefinitionRegistry, falls back to its parent for names it does not define.
 type DefaultDefRegistry struct {
-	readonly  *atomic.Bool
-	entries   map[string][]*Definition
-	factories map[string]*Definition
-	inSeq     []string
+	parent       DefinitionRegistry
+	readonly     *atomic.Bool
+	entries      map[string][]*Definition
+	factories    map[string]*Definition
+	inSeq        []string
+	rnd          *rand.Rand
+	scopePolicy  ScopePolicy
+	overriding   bool
+	profiles     []string
+	excluded     []Exclusion
+	values       map[string]any
+	valueSources []ValueSource
+	resolver     ProviderResolver
 }
 
 // NewDefinitionRegistry creates and returns a new DefinitionRegistry with
@@ -74,13 +117,37 @@ func NewDefinitionRegistry() *DefaultDefRegistry {
 	readonly := &atomic.Bool{}
 	readonly.Store(false)
 	return &DefaultDefRegistry{
-		readonly:  readonly,
-		entries:   map[string][]*Definition{},
-		factories: map[string]*Definition{},
-		inSeq:     []string{},
+		readonly:    readonly,
+		entries:     map[string][]*Definition{},
+		factories:   map[string]*Definition{},
+		inSeq:       []string{},
+		scopePolicy: ScopePolicyWarn,
+		values:      map[string]any{},
 	}
 }
 
+// NewChildDefinitionRegistry creates a registry whose lookups fall back to the parent for names it does not
+// define itself. Definitions registered in the child shadow the parent's definitions of the same name,
+// even when registered as unique; GetDefinitions only returns the child's own definitions.
+func NewChildDefinitionRegistry(parent DefinitionRegistry) *DefaultDefRegistry {
+	dr := NewDefinitionRegistry()
+	dr.parent = parent
+	return dr
+}
+
+// Contains returns true if the definition is registered in this registry itself rather than inherited from a parent.
+func (dr *DefaultDefRegistry) Contains(def *Definition) bool {
+	if def == nil {
+		return false
+	}
+	return dr.factories[def.ID()] == def
+}
+
+// SetScopePolicy sets how scope violations, such as a Singleton capturing a Prototype, are treated during Init.
+func (dr *DefaultDefRegistry) SetScopePolicy(policy ScopePolicy) {
+	dr.scopePolicy = policy
+}
+
 // RegisterFactory registers a factory function with the given property, returning a new Definition.
 func (dr *DefaultDefRegistry) RegisterFactory(fn any, prop *Property, unique bool) (*Definition, error) {
 	def, err := ParseDefinition(fn, prop)
@@ -94,20 +161,87 @@ func (dr *DefaultDefRegistry) RegisterFactory(fn any, prop *Property, unique boo
 	return def, nil
 }
 
-// Init locks the DefinitionRegistry, sorts and checks for circular dependencies, then logs the process.
+// SetAllowOverriding sets whether a registration colliding with an existing definition, either a unique
+// registration of a known name or a known factory function, replaces that definition instead of failing.
+// Definitions created with Property.Override replace existing definitions regardless of this policy.
+func (dr *DefaultDefRegistry) SetAllowOverriding(allow bool) {
+	dr.overriding = allow
+}
+
+// SetProfiles sets the active profiles conditions are evaluated against during Init. Without it, the profiles
+// are read from the ProfilesEnvKey environment variable.
+func (dr *DefaultDefRegistry) SetProfiles(profiles ...string) {
+	dr.profiles = append([]string{}, profiles...)
+}
+
+// Excluded returns the definitions whose conditions did not match during Init, with the reasons.
+func (dr *DefaultDefRegistry) Excluded() []Exclusion {
+	return append([]Exclusion{}, dr.excluded...)
+}
+
+// Replace removes every definition registered under the definition's name and registers the definition
+// in their place. It fails once the registry has been locked by Init.
+func (dr *DefaultDefRegistry) Replace(def *Definition) error {
+	if dr.readonly.Load() {
+		return errors.New("the DefinitionRegistry has been locked")
+	}
+	if !def.IsValid() {
+		return errors.New("definition not valid")
+	}
+	replaced := dr.entries[def.Name()]
+	dr.remove(replaced...)
+	logOverrides(replaced, def)
+	return dr.register(def, true)
+}
+
+// RegisterDefinition registers an already parsed Definition, such as one created by ParseInstance,
+// following the same rules as RegisterFactory.
+func (dr *DefaultDefRegistry) RegisterDefinition(def *Definition, unique bool) error {
+	return dr.register(def, unique)
+}
+
+// SetRandomOrder makes Init shuffle the order of independent definitions, and of definitions sharing
+// a name, using the given seed. It is intended for tests that want to surface hidden order dependencies;
+// the seed is logged at Init so a failing order can be reproduced.
+func (dr *DefaultDefRegistry) SetRandomOrder(seed int64) {
+	dr.rnd = rand.New(rand.NewSource(seed))
+	util.Logger().Info("the DefinitionRegistry uses randomized order", zap.Int64("seed", seed))
+}
+
+// Init locks the DefinitionRegistry, removes the definitions whose conditions do not match, validates the
+// remaining definitions, sorts and checks for circular dependencies, then logs the process.
+// Every error found by Validate is returned at once.
 func (dr *DefaultDefRegistry) Init() error {
 	dr.readonly.Store(true)
-	util.Logger().Info("the DefinitionRegistry has been locked")
+	l := util.Logger()
+	l.Info("the DefinitionRegistry has been locked")
+	dr.evaluateConditions()
+	if err := dr.checkUnique(); err != nil {
+		l.Error("validation failed", zap.Error(err))
+		return err
+	}
+	report := dr.Validate()
+	for _, warn := range report.Warnings() {
+		l.Warn("validation warning", zap.String("detail", warn))
+	}
+	if err := report.Err(); err != nil {
+		l.Error("validation failed", zap.String("report", report.String()))
+		return err
+	}
 	if err := dr.sortAndCheck(); err != nil {
 		return err
 	}
-	return nil
+	return dr.bindValues()
 }
 
 // GetDefinitions returns a list of definitions that match all the provided filters.
 func (dr *DefaultDefRegistry) GetDefinitions(filters ...DefinitionFilter) []*Definition {
 	var result []*Definition
-	for _, def := range dr.factories {
+	for _, fid := range dr.inSeq {
+		def, ok := dr.factories[fid]
+		if !ok {
+			continue
+		}
 		matched := true
 		for _, filter := range filters {
 			if filter != nil && !filter(def) {
@@ -123,8 +257,12 @@ func (dr *DefaultDefRegistry) GetDefinitions(filters ...DefinitionFilter) []*Def
 }
 
 // GetDefinitionsByName retrieves definitions by name, optionally filtered by provided DefinitionFilter functions.
+// If the registry defines no definition of that name, the parent registry, if any, is queried instead.
 func (dr *DefaultDefRegistry) GetDefinitionsByName(name string, filters ...DefinitionFilter) []*Definition {
 	defs := dr.entries[name]
+	if len(defs) == 0 && dr.parent != nil {
+		return dr.parent.GetDefinitionsByName(name, filters...)
+	}
 	if len(filters) == 0 {
 		return defs
 	}
@@ -182,21 +320,76 @@ func (dr *DefaultDefRegistry) register(def *Definition, unique bool) error {
 	if !def.IsValid() {
 		return errors.New("definition not valid")
 	}
-	if unique {
-		if _, ok := dr.entries[def.Name()]; ok {
+	var replaced []*Definition
+	existing := dr.entries[def.Name()]
+	// alternatives selected by conditions are checked for uniqueness once Init has evaluated the conditions
+	deferUnique := !def.Override() && isConditional(append([]*Definition{def}, existing...)...)
+	if len(existing) > 0 && (unique || def.Override()) && !deferUnique {
+		if !def.Override() && !dr.overriding {
 			return fmt.Errorf("object type %s does not allow duplicate definition", def.Name())
 		}
+		replaced = append(replaced, existing...)
 	}
 	fid := def.Factory().Name()
-	if _, ok := dr.factories[fid]; ok {
-		return fmt.Errorf("definition's factory function %s already exists", fid)
+	if old, ok := dr.factories[fid]; ok && !slices.Contains(replaced, old) {
+		if !def.Override() && !dr.overriding {
+			return fmt.Errorf("definition's factory function %s already exists", fid)
+		}
+		replaced = append(replaced, old)
 	}
+	if dr.parent != nil && len(dr.parent.GetDefinitionsByName(def.Name())) > 0 {
+		util.Logger().Debug("definition overrides parent", zap.String("definition", def.ID()))
+	}
+	dr.remove(replaced...)
+	logOverrides(replaced, def)
+	def.unique = unique
 	dr.factories[fid] = def
 	dr.entries[def.Name()] = append(dr.entries[def.Name()], def)
 	dr.inSeq = append(dr.inSeq, fid)
 	return nil
 }
 
+// remove deletes the definitions from entries, factories and inSeq.
+func (dr *DefaultDefRegistry) remove(defs ...*Definition) {
+	if len(defs) == 0 {
+		return
+	}
+	removed := map[*Definition]bool{}
+	for _, def := range defs {
+		removed[def] = true
+	}
+	for _, def := range defs {
+		delete(dr.factories, def.ID())
+		var kept []*Definition
+		for _, entry := range dr.entries[def.Name()] {
+			if !removed[entry] {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) == 0 {
+			delete(dr.entries, def.Name())
+		} else {
+			dr.entries[def.Name()] = kept
+		}
+	}
+	inSeq := make([]string, 0, len(dr.inSeq))
+	for _, fid := range dr.inSeq {
+		if _, ok := dr.factories[fid]; ok {
+			inSeq = append(inSeq, fid)
+		}
+	}
+	dr.inSeq = inSeq
+}
+
+// logOverrides logs every definition replaced by def together with the factory locations of both.
+func logOverrides(replaced []*Definition, def *Definition) {
+	for _, old := range replaced {
+		util.Logger().Info("definition overridden",
+			zap.String("definition", old.ID()), zap.String("location", location(old)),
+			zap.String("replacement", def.ID()), zap.String("replacementLocation", location(def)))
+	}
+}
+
 /*
 In theory, at this point all component dependencies should have been registered, and there should be
 no dependency cycles (constructor injection mode).
@@ -219,9 +412,12 @@ During system initialization, the following should be checked in advance:
 To simplify system design, code with dependency cycles is not allowed.
 */
 func (dr *DefaultDefRegistry) sortAndCheck() error {
-	dag, defs := util.NewDAG(), dr.factories
-	for _, def := range defs {
-		dag.AddNode(def.Name(), def.DependsOn()...)
+	dag := util.NewDAG()
+	dag.SetRand(dr.rnd)
+	for _, fid := range dr.inSeq {
+		if def, ok := dr.factories[fid]; ok {
+			dag.AddNode(def.Name(), def.DependsOn()...)
+		}
 	}
 	sorted, err := dag.Sort()
 	if err != nil {
@@ -230,11 +426,17 @@ func (dr *DefaultDefRegistry) sortAndCheck() error {
 	inSeq := []string{}
 	for _, name := range sorted {
 		defs, ok := dr.entries[name]
+		if !ok && dr.parent != nil && len(dr.parent.GetDefinitionsByName(name)) > 0 {
+			continue
+		}
 		if !ok {
 			err := fmt.Errorf("definition not found: %s", name)
 			util.Logger().Error("validation failed", zap.String("name", name), zap.Error(err))
 			return err
 		}
+		if dr.rnd != nil {
+			dr.rnd.Shuffle(len(defs), func(i, j int) { defs[i], defs[j] = defs[j], defs[i] })
+		}
 		for _, def := range defs {
 			util.Logger().Debug("validation passed",
 				zap.String("name", def.Name()),
@@ -2,6 +2,7 @@ package object
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 )
@@ -518,4 +519,190 @@ func TestDefinitionRegistry_sortAndCheck_SuccessOrderAndInSeqUpdate(t *testing.T
 	}
 }
 
+func TestDefinitionRegistry_GetDefinitions_RegistrationOrder(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	names := []string{"fz", "fa", "fm", "fb"}
+	for i, fid := range names {
+		_ = reg.register(makeTestDefinition(fmt.Sprintf("N%d", i), fid, nil), false)
+	}
+	for i := 0; i < 10; i++ {
+		defs := reg.GetDefinitions()
+		for j, def := range defs {
+			if def.ID() != names[j] {
+				t.Fatalf("expected registration order %v, got %v", names, defs)
+			}
+		}
+	}
+	if err := reg.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	for j, def := range reg.GetDefinitions() {
+		if def.ID() != names[j] {
+			t.Fatalf("independent definitions should keep registration order after Init")
+		}
+	}
+}
+
+func TestDefinitionRegistry_SetRandomOrder_KeepsDependencies(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		reg := NewDefinitionRegistry()
+		reg.SetRandomOrder(seed)
+		root := makeTestDefinition("Root", "froot", nil)
+		root.dependsOn = []string{"Dep"}
+		_ = reg.register(root, false)
+		_ = reg.register(makeTestDefinition("Dep", "fdep1", nil), false)
+		_ = reg.register(makeTestDefinition("Dep", "fdep2", nil), false)
+		_ = reg.register(makeTestDefinition("Other", "fother", nil), false)
+		if err := reg.Init(); err != nil {
+			t.Fatalf("Init failed: %v", err)
+		}
+		index := map[string]int{}
+		for i, v := range reg.inSeq {
+			index[v] = i
+		}
+		if index["fdep1"] > index["froot"] || index["fdep2"] > index["froot"] {
+			t.Fatalf("seed %d: dependencies must precede root: %v", seed, reg.inSeq)
+		}
+		if len(reg.GetDefinitionsByName("Dep")) != 2 {
+			t.Fatalf("seed %d: shuffling must not drop definitions", seed)
+		}
+	}
+}
+
+func TestProperty_GetTags_SortedByKey(t *testing.T) {
+	prop := NewProperty()
+	prop.SetTags(NewTag("z", "1"), NewTag("a", "2"), NewTag("m", "3"))
+	tags := prop.GetTags()
+	if tags[0].Key() != "a" || tags[1].Key() != "m" || tags[2].Key() != "z" {
+		t.Fatalf("tags should be sorted by key: %v", tags)
+	}
+}
+
 // --- 新增测试结束 ---
+
+func TestDefinitionRegistry_Child_FallbackAndOverride(t *testing.T) {
+	parent := NewDefinitionRegistry()
+	base := makeTestDefinition("svc", "parentSvc", nil)
+	dep := makeTestDefinition("dep", "parentDep", nil)
+	if err := parent.register(base, true); err != nil {
+		t.Fatalf("register parent failed: %v", err)
+	}
+	if err := parent.register(dep, true); err != nil {
+		t.Fatalf("register parent dep failed: %v", err)
+	}
+
+	child := NewChildDefinitionRegistry(parent)
+	override := makeTestDefinition("svc", "childSvc", nil)
+	override.dependsOn = []string{"dep"}
+	if err := child.register(override, true); err != nil {
+		t.Fatalf("unique child definition should be allowed to override the parent: %v", err)
+	}
+	if defs := child.GetDefinitionsByName("svc"); len(defs) != 1 || defs[0] != override {
+		t.Fatalf("child definition should shadow the parent's, got %v", defs)
+	}
+	if defs := child.GetDefinitionsByName("dep"); len(defs) != 1 || defs[0] != dep {
+		t.Fatalf("child lookup should fall back to the parent, got %v", defs)
+	}
+	if !child.Contains(override) || child.Contains(dep) || !parent.Contains(dep) {
+		t.Fatalf("Contains should only report the registry's own definitions")
+	}
+	if defs := child.GetDefinitions(); len(defs) != 1 {
+		t.Fatalf("GetDefinitions should only return the child's own definitions, got %d", len(defs))
+	}
+	if err := child.Init(); err != nil {
+		t.Fatalf("child Init should resolve dependencies through the parent: %v", err)
+	}
+}
+
+func TestDefinitionRegistry_Replace(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	a := makeTestDefinition("svc", "fa", nil)
+	b := makeTestDefinition("svc", "fb", nil)
+	other := makeTestDefinition("other", "fo", nil)
+	for _, def := range []*Definition{a, b, other} {
+		if err := reg.register(def, false); err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+	}
+	repl := makeTestDefinition("svc", "fr", nil)
+	if err := reg.Replace(repl); err != nil {
+		t.Fatalf("replace failed: %v", err)
+	}
+	if defs := reg.GetDefinitionsByName("svc"); len(defs) != 1 || defs[0] != repl {
+		t.Fatalf("replace should leave only the replacement, got %v", defs)
+	}
+	if reg.Contains(a) || reg.Contains(b) {
+		t.Fatalf("replaced definitions should be removed from factories")
+	}
+	if got := reg.GetDefinitions(); len(got) != 2 || got[0] != other || got[1] != repl {
+		t.Fatalf("unexpected definitions after replace: %v", got)
+	}
+	reg.readonly.Store(true)
+	if err := reg.Replace(makeTestDefinition("svc", "fz", nil)); err == nil {
+		t.Fatalf("replace should fail once locked")
+	}
+}
+
+func TestDefinitionRegistry_Register_Override(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	a := makeTestDefinition("svc", "fa", nil)
+	b := makeTestDefinition("svc", "fb", nil)
+	other := makeTestDefinition("other", "fo", nil)
+	for _, def := range []*Definition{a, b, other} {
+		if err := reg.register(def, false); err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+	}
+	over := makeTestDefinition("svc", "fover", nil)
+	over.override = true
+	if err := reg.register(over, true); err != nil {
+		t.Fatalf("override registration failed: %v", err)
+	}
+	if defs := reg.GetDefinitionsByName("svc"); len(defs) != 1 || defs[0] != over {
+		t.Fatalf("override should replace every definition of the name, got %v", defs)
+	}
+	if reg.Contains(a) || reg.Contains(b) {
+		t.Fatalf("replaced definitions should be removed from factories")
+	}
+	if got := reg.GetDefinitions(); len(got) != 2 || got[0] != other || got[1] != over {
+		t.Fatalf("replaced definitions should be removed from inSeq, got %v", got)
+	}
+}
+
+func TestDefinitionRegistry_Register_AllowOverridingPolicy(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	first := makeTestDefinition("svc", "fa", nil)
+	if err := reg.register(first, true); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if err := reg.register(makeTestDefinition("svc", "fb", nil), true); err == nil {
+		t.Fatalf("duplicate unique registration should fail without the policy")
+	}
+	if err := reg.register(makeTestDefinition("x", "fa", nil), false); err == nil {
+		t.Fatalf("duplicate factory should fail without the policy")
+	}
+
+	reg.SetAllowOverriding(true)
+	second := makeTestDefinition("svc", "fb", nil)
+	if err := reg.register(second, true); err != nil {
+		t.Fatalf("policy should allow overriding: %v", err)
+	}
+	if defs := reg.GetDefinitionsByName("svc"); len(defs) != 1 || defs[0] != second {
+		t.Fatalf("unexpected definitions %v", defs)
+	}
+	// 同一工厂函数重复注册：旧定义被替换
+	moved := makeTestDefinition("moved", "fb", nil)
+	if err := reg.register(moved, false); err != nil {
+		t.Fatalf("policy should allow overriding a factory: %v", err)
+	}
+	if len(reg.GetDefinitionsByName("svc")) != 0 || len(reg.GetDefinitions()) != 1 {
+		t.Fatalf("definition of the overridden factory should be removed everywhere")
+	}
+	// 非唯一注册不受策略影响，允许多实现共存
+	if err := reg.register(makeTestDefinition("moved", "fc", nil), false); err != nil {
+		t.Fatalf("non-unique registration failed: %v", err)
+	}
+	if len(reg.GetDefinitionsByName("moved")) != 2 {
+		t.Fatalf("non-unique registrations should coexist")
+	}
+}
@@ -647,3 +647,59 @@ func TestParser_Parse_WithPropertyOverrides(t *testing.T) {
 		t.Fatalf("expected empty desc (property.Desc not propagated), got %q", def.Desc())
 	}
 }
+
+type instanceIface interface{ Name() string }
+type instanceImpl struct{}
+
+func (instanceImpl) Name() string { return "impl" }
+
+func TestParseInstance(t *testing.T) {
+	typ := reflect.TypeOf((*instanceIface)(nil)).Elem()
+	d1, err := ParseInstance(typ, instanceImpl{}, NewProperty())
+	if err != nil {
+		t.Fatalf("parse instance failed: %v", err)
+	}
+	d2, err := ParseInstance(typ, instanceImpl{}, NewProperty())
+	if err != nil {
+		t.Fatalf("parse instance failed: %v", err)
+	}
+	if d1.Name() != GenerateDefinitionName(typ) {
+		t.Fatalf("unexpected name %s", d1.Name())
+	}
+	if d1.ID() == d2.ID() {
+		t.Fatalf("every instance definition should have a distinct factory id")
+	}
+	if !strings.HasSuffix(d1.Factory().File(), "definition_test.go") {
+		t.Fatalf("factory location should be the caller, got %s", d1.Factory().File())
+	}
+	out := d1.Factory().Call(nil).Interface().(instanceIface)
+	if out.Name() != "impl" {
+		t.Fatalf("factory should return the instance")
+	}
+	if _, err := ParseInstance(typ, 42, NewProperty()); err == nil {
+		t.Fatalf("non-assignable instance should be rejected")
+	}
+}
+
+// 每次调用工厂都应返回 supplier 的当前结果
+func TestParseSupplier(t *testing.T) {
+	typ := reflect.TypeOf((*instanceIface)(nil)).Elem()
+	var current any = instanceImpl{}
+	def, err := ParseSupplier(typ, func() any { return current }, NewProperty())
+	if err != nil {
+		t.Fatalf("parse supplier failed: %v", err)
+	}
+	if !strings.HasPrefix(def.ID(), "supplier(") || !strings.HasSuffix(def.Factory().File(), "definition_test.go") {
+		t.Fatalf("unexpected factory id or location: %s %s", def.ID(), def.Factory().File())
+	}
+	if out, _ := def.Factory().Call(nil).Interface().(instanceIface); out == nil || out.Name() != "impl" {
+		t.Fatalf("factory should return the supplied value")
+	}
+	current = 42
+	if out := def.Factory().Call(nil).Interface(); out != nil {
+		t.Fatalf("non-assignable result should yield the zero value, got %v", out)
+	}
+	if _, err := ParseSupplier(typ, nil, NewProperty()); err == nil {
+		t.Fatalf("nil supplier should be rejected")
+	}
+}
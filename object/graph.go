@@ -0,0 +1,188 @@
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GraphNode describes a single definition in the component graph. Definitions of a parent registry that
+// definitions of a child registry depend on are included with Inherited set.
+type GraphNode struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Factory     string   `json:"factory"`
+	Location    string   `json:"location"`
+	Scope       Scope    `json:"scope"`
+	Tags        []string `json:"tags"`
+	LazyInit    bool     `json:"lazyInit"`
+	AutoStartup bool     `json:"autoStartup"`
+	Inherited   bool     `json:"inherited,omitempty"`
+}
+
+// GraphEdge describes a dependency from one definition to another. When no definition provides
+// the dependency, To holds the dependency name and Missing is true.
+type GraphEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Dependency string `json:"dependency"`
+	Missing    bool   `json:"missing,omitempty"`
+}
+
+// Graph is a snapshot of the component graph held by a DefinitionRegistry.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// Graph returns the component graph: one node per definition and one edge per dependency candidate,
+// in the registry's stable definition order. Candidates inherited from a parent registry follow as
+// inherited nodes, in edge order.
+func (dr *DefaultDefRegistry) Graph() *Graph {
+	g := &Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	own := map[string]bool{}
+	for _, def := range dr.GetDefinitions() {
+		own[def.ID()] = true
+		g.Nodes = append(g.Nodes, newGraphNode(def, false))
+	}
+	var inherited []GraphNode
+	for _, def := range dr.GetDefinitions() {
+		for _, dep := range def.DependsOn() {
+			candidates := dr.GetDefinitionsByName(dep)
+			if len(candidates) == 0 {
+				g.Edges = append(g.Edges, GraphEdge{From: def.ID(), To: dep, Dependency: dep, Missing: true})
+				continue
+			}
+			for _, candidate := range candidates {
+				if !own[candidate.ID()] {
+					own[candidate.ID()] = true
+					inherited = append(inherited, newGraphNode(candidate, true))
+				}
+				g.Edges = append(g.Edges, GraphEdge{From: def.ID(), To: candidate.ID(), Dependency: dep})
+			}
+		}
+	}
+	g.Nodes = append(g.Nodes, inherited...)
+	return g
+}
+
+// newGraphNode returns the node describing the definition.
+func newGraphNode(def *Definition, inherited bool) GraphNode {
+	tags := []string{}
+	for _, tag := range def.Tags() {
+		tags = append(tags, tag.String())
+	}
+	return GraphNode{
+		ID:          def.ID(),
+		Name:        def.Name(),
+		Factory:     def.Factory().Name(),
+		Location:    location(def),
+		Scope:       def.Scope(),
+		Tags:        tags,
+		LazyInit:    def.LazyInit(),
+		AutoStartup: def.AutoStartup(),
+		Inherited:   inherited,
+	}
+}
+
+// WriteJSON writes the graph as indented JSON.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Missing dependencies are drawn as dashed red nodes and
+// inherited definitions as gray nodes.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	sb := &strings.Builder{}
+	sb.WriteString("digraph vortice {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+	for _, n := range g.Nodes {
+		style := ""
+		if n.Scope == Prototype {
+			style = ", style=dashed"
+		}
+		if n.AutoStartup {
+			style += ", peripheries=2"
+		}
+		if n.Inherited {
+			style += ", color=gray, fontcolor=gray"
+		}
+		fmt.Fprintf(sb, "  %q [label=%q%s];\n", n.ID, n.label("\n"), style)
+	}
+	for _, name := range g.missing() {
+		fmt.Fprintf(sb, "  %q [label=%q, color=red, fontcolor=red, style=dashed];\n",
+			name, "missing\n"+name)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(sb, "  %q -> %q;\n", e.From, e.To)
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart. Missing dependencies are styled in red and inherited
+// definitions in gray.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	sb := &strings.Builder{}
+	sb.WriteString("flowchart LR\n")
+	ids := map[string]string{}
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(sb, "  %s[\"%s\"]\n", ids[n.ID], mermaidEscape(n.label("<br/>")))
+		if n.Inherited {
+			fmt.Fprintf(sb, "  style %s stroke:#888,color:#888\n", ids[n.ID])
+		}
+	}
+	for i, name := range g.missing() {
+		ids[name] = fmt.Sprintf("m%d", i)
+		fmt.Fprintf(sb, "  %s[\"missing<br/>%s\"]\n", ids[name], mermaidEscape(name))
+		fmt.Fprintf(sb, "  style %s stroke:#f00,color:#f00,stroke-dasharray:4\n", ids[name])
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(sb, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// label returns the node description, its lines joined by sep.
+func (n GraphNode) label(sep string) string {
+	flags := []string{string(n.Scope)}
+	if n.LazyInit {
+		flags = append(flags, "lazy")
+	}
+	if n.AutoStartup {
+		flags = append(flags, "autostart")
+	}
+	if n.Inherited {
+		flags = append(flags, "inherited")
+	}
+	lines := []string{n.Name, n.Factory, n.Location, strings.Join(flags, ",")}
+	if len(n.Tags) > 0 {
+		lines = append(lines, strings.Join(n.Tags, ","))
+	}
+	return strings.Join(lines, sep)
+}
+
+// missing returns the names of missing dependencies in edge order, without duplicates.
+func (g *Graph) missing() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, e := range g.Edges {
+		if e.Missing && !seen[e.To] {
+			seen[e.To] = true
+			names = append(names, e.To)
+		}
+	}
+	return names
+}
+
+// mermaidEscape replaces characters that would terminate a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, "\"", "#quot;")
+}
@@ -0,0 +1,103 @@
+package object
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newGraphTestRegistry() *DefaultDefRegistry {
+	reg := NewDefinitionRegistry()
+	root := makeTestDefinition("Root", "froot", []Tag{NewTag("k", "v")})
+	root.dependsOn = []string{"Dep", "Ghost"}
+	dep := makeTestDefinition("Dep", "fdep", nil)
+	dep.scope = Prototype
+	_ = reg.register(root, false)
+	_ = reg.register(dep, false)
+	return reg
+}
+
+func TestGraph_NodesAndEdges(t *testing.T) {
+	g := newGraphTestRegistry().Graph()
+	if len(g.Nodes) != 2 || g.Nodes[0].ID != "froot" || g.Nodes[1].Scope != Prototype {
+		t.Fatalf("unexpected nodes: %+v", g.Nodes)
+	}
+	if len(g.Nodes[0].Tags) != 1 || g.Nodes[0].Tags[0] != "k=v" {
+		t.Fatalf("tags not exported: %+v", g.Nodes[0])
+	}
+	if len(g.Edges) != 2 {
+		t.Fatalf("expected 2 edges, got %+v", g.Edges)
+	}
+	if g.Edges[0].To != "fdep" || g.Edges[0].Missing {
+		t.Fatalf("edge to existing definition should target its factory: %+v", g.Edges[0])
+	}
+	if g.Edges[1].To != "Ghost" || !g.Edges[1].Missing {
+		t.Fatalf("edge to missing dependency should be marked: %+v", g.Edges[1])
+	}
+}
+
+func TestGraph_Encoders(t *testing.T) {
+	g := newGraphTestRegistry().Graph()
+
+	dot := &bytes.Buffer{}
+	if err := g.WriteDOT(dot); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	if !strings.Contains(dot.String(), `"froot" -> "fdep";`) || !strings.Contains(dot.String(), "color=red") {
+		t.Fatalf("unexpected DOT output:\n%s", dot)
+	}
+
+	mmd := &bytes.Buffer{}
+	if err := g.WriteMermaid(mmd); err != nil {
+		t.Fatalf("WriteMermaid failed: %v", err)
+	}
+	if !strings.Contains(mmd.String(), "n0 --> n1") || !strings.Contains(mmd.String(), "n0 --> m0") {
+		t.Fatalf("unexpected Mermaid output:\n%s", mmd)
+	}
+
+	js := &bytes.Buffer{}
+	if err := g.WriteJSON(js); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var decoded Graph
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON output not decodable: %v", err)
+	}
+	if len(decoded.Nodes) != 2 || len(decoded.Edges) != 2 {
+		t.Fatalf("JSON round trip mismatch: %+v", decoded)
+	}
+}
+
+// 子注册表的图应包含从父注册表继承的依赖节点
+func TestGraph_ChildRegistry(t *testing.T) {
+	parent := NewDefinitionRegistry()
+	_ = parent.register(makeTestDefinition("Dep", "fdep", nil), false)
+	child := NewChildDefinitionRegistry(parent)
+	root := makeTestDefinition("Root", "froot", nil)
+	root.dependsOn = []string{"Dep"}
+	_ = child.register(root, false)
+
+	g := child.Graph()
+	if len(g.Nodes) != 2 || g.Nodes[0].Inherited || g.Nodes[1].ID != "fdep" || !g.Nodes[1].Inherited {
+		t.Fatalf("inherited dependency should be exported as a node: %+v", g.Nodes)
+	}
+	if len(g.Edges) != 1 || g.Edges[0].To != "fdep" || g.Edges[0].Missing {
+		t.Fatalf("unexpected edges: %+v", g.Edges)
+	}
+
+	mmd := &bytes.Buffer{}
+	if err := g.WriteMermaid(mmd); err != nil {
+		t.Fatalf("WriteMermaid failed: %v", err)
+	}
+	if !strings.Contains(mmd.String(), "n0 --> n1\n") || !strings.Contains(mmd.String(), "style n1 stroke:#888") {
+		t.Fatalf("unexpected Mermaid output:\n%s", mmd)
+	}
+	dot := &bytes.Buffer{}
+	if err := g.WriteDOT(dot); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	if !strings.Contains(dot.String(), `"fdep" [label="Dep`) || !strings.Contains(dot.String(), "color=gray") {
+		t.Fatalf("unexpected DOT output:\n%s", dot)
+	}
+}
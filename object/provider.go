@@ -0,0 +1,73 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+
+	"vortice/util"
+
+	"go.uber.org/zap"
+)
+
+// Provider is a factory argument deferring the lookup of a dependency to every call, so a longer-lived
+// object can obtain a fresh Prototype each time it needs one instead of capturing a single instance at
+// construction. An argument of any type func() T is injected the same way. Providers are resolved by the
+// container the definition is registered in; they must not be called from the factory receiving them.
+//
+//	func NewHandler(session object.Provider[*Session]) *Handler
+type Provider[T any] func() T
+
+// ProviderResolver returns the object of the definition selected for a dependency name, created as its
+// scope requires.
+type ProviderResolver func(name string) (reflect.Value, error)
+
+// Providers returns the names of the dependencies the definition receives through providers. They are not
+// part of DependsOn, as they are only resolved when the provider is called.
+func (d *Definition) Providers() []string {
+	return append([]string{}, d.providers...)
+}
+
+// providedType returns the type a provider argument type yields, if the type is a function taking no
+// arguments and returning a single valid dependency type.
+func (p *Parser) providedType(rt reflect.Type) (reflect.Type, bool) {
+	if rt.Kind() != reflect.Func || rt.NumIn() != 0 || rt.NumOut() != 1 {
+		return nil, false
+	}
+	if p.checkArgType(rt.Out(0)) != nil {
+		return nil, false
+	}
+	return rt.Out(0), true
+}
+
+// addProvider adds a provider argument of type rt resolving the definition of the elem type.
+func (p *Parser) addProvider(rt, elem reflect.Type) {
+	name := p.generateDefinitionName(elem)
+	p.providers = append(p.providers, name)
+	p.params = append(p.params, inParam{typ: rt, provides: name})
+}
+
+// provider returns a function of type rt resolving the named dependency with the bound resolver on
+// every call. A dependency that cannot be resolved panics, as the function has no error result.
+func (b *valueBinding) provider(rt reflect.Type, name string) reflect.Value {
+	return reflect.MakeFunc(rt, func([]reflect.Value) []reflect.Value {
+		var (
+			v   reflect.Value
+			err = fmt.Errorf("no resolver is bound to the provider of %s", name)
+		)
+		if b.resolve != nil {
+			v, err = b.resolve(name)
+		}
+		if err != nil {
+			util.Logger().Panic("Provider", zap.String("dependency", name), zap.Error(err))
+		}
+		out := reflect.New(rt.Out(0)).Elem()
+		out.Set(v)
+		return []reflect.Value{out}
+	})
+}
+
+// SetProviderResolver sets the resolver bound during Init to the providers of the definitions registered
+// in this registry.
+func (dr *DefaultDefRegistry) SetProviderResolver(resolver ProviderResolver) {
+	dr.resolver = resolver
+}
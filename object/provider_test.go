@@ -0,0 +1,76 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type providedDep struct{ n int }
+type providerRoot struct {
+	dep  *providedDep
+	next Provider[*providedDep]
+}
+
+func newProviderRoot(dep *providedDep, next Provider[*providedDep]) *providerRoot {
+	return &providerRoot{dep: dep, next: next}
+}
+
+// provider 参数不计入 DependsOn，调用时通过绑定的 resolver 解析
+func TestParseDefinition_Provider(t *testing.T) {
+	def, err := ParseDefinition(newProviderRoot, NewProperty())
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	name := GenerateDefinitionName(reflect.TypeOf(&providedDep{}))
+	if deps := def.DependsOn(); len(deps) != 1 || deps[0] != name {
+		t.Fatalf("expected only the direct dependency, got %v", deps)
+	}
+	if providers := def.Providers(); len(providers) != 1 || providers[0] != name {
+		t.Fatalf("expected the provided dependency, got %v", providers)
+	}
+	calls := 0
+	def.binding.resolve = func(got string) (reflect.Value, error) {
+		calls++
+		return reflect.ValueOf(&providedDep{n: calls}), nil
+	}
+	root := def.Factory().Call([]reflect.Value{reflect.ValueOf(&providedDep{})}).Interface().(*providerRoot)
+	if calls != 0 {
+		t.Fatalf("provider should not resolve at construction")
+	}
+	if root.next().n != 1 || root.next().n != 2 {
+		t.Fatalf("expected a new resolution on every call")
+	}
+}
+
+// 只接受无参数、返回合法依赖类型的函数
+func TestParseDefinition_InvalidProvider(t *testing.T) {
+	for _, fn := range []any{
+		func(func(int) *providedDep) *providerRoot { return nil },
+		func(func() int) *providerRoot { return nil },
+	} {
+		if _, err := ParseDefinition(fn, NewProperty()); err == nil || !strings.Contains(err.Error(), "invalid argument type") {
+			t.Fatalf("expected invalid argument type, got %v", err)
+		}
+	}
+}
+
+// 通过 provider 依赖的 prototype 不算作用域违规，但缺失时仍然报错
+func TestValidate_Provider(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	reg.SetScopePolicy(ScopePolicyError)
+	root := makeTestDefinition("Root", "froot", nil)
+	root.providers = []string{"Proto", "Absent"}
+	proto := makeTestDefinition("Proto", "fproto", nil)
+	proto.scope = Prototype
+	for _, def := range []*Definition{root, proto} {
+		_ = reg.register(def, false)
+	}
+	report := reg.Validate()
+	if len(report.ScopeViolations) != 0 {
+		t.Fatalf("providers should not widen scopes, got %v", report.ScopeViolations)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].Name != "Absent" {
+		t.Fatalf("expected the missing provided dependency, got %v", report.Missing)
+	}
+}
@@ -0,0 +1,282 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"vortice/util"
+)
+
+type (
+	// ScopePolicy decides how the registry treats scope violations found during Init.
+	ScopePolicy string
+)
+
+const (
+	// ScopePolicyError makes scope violations fail Init.
+	ScopePolicyError ScopePolicy = "error"
+	// ScopePolicyWarn logs scope violations as warnings; this is the default.
+	ScopePolicyWarn ScopePolicy = "warn"
+	// ScopePolicyAllow ignores scope violations entirely.
+	ScopePolicyAllow ScopePolicy = "allow"
+)
+
+// scopeLifetimes ranks scopes from the shortest to the longest lived; a definition must not
+// depend on a definition with a lower rank. Scopes missing from the map are never checked.
+var scopeLifetimes = map[Scope]int{
+	Prototype: 0,
+	Singleton: 100,
+}
+
+// widensScope returns true if a definition with scope outer captures a dependency with the shorter-lived scope inner.
+func widensScope(outer, inner Scope) bool {
+	o, ok0 := scopeLifetimes[outer]
+	i, ok1 := scopeLifetimes[inner]
+	return ok0 && ok1 && i < o
+}
+
+type (
+	// MissingDependency describes a dependency that no registered definition provides.
+	MissingDependency struct {
+		// Name is the definition name of the missing dependency.
+		Name string
+		// RequiredBy is the definition whose factory requests the dependency.
+		RequiredBy *Definition
+		// Excluded are the definitions of the dependency removed during Init because their conditions did not match.
+		Excluded []Exclusion
+	}
+	// DependencyCycle is a closed path of definition names, the first name repeated at the end.
+	DependencyCycle []string
+	// AmbiguousDependency describes a dependency provided by more than one definition,
+	// leaving the choice to the RealizationSelector at runtime.
+	AmbiguousDependency struct {
+		// Name is the definition name of the dependency.
+		Name string
+		// RequiredBy is the definition whose factory requests the dependency.
+		RequiredBy *Definition
+		// Candidates are the definitions registered under Name.
+		Candidates []*Definition
+	}
+	// FailedCheck describes a definition whose check, added with Property.AddChecks, returned an error.
+	FailedCheck struct {
+		// Definition is the definition the check belongs to.
+		Definition *Definition
+		// Err is the error returned by the check.
+		Err error
+	}
+	// InvalidValue describes a named value requested through an In struct that is not set or cannot be
+	// converted to the requested type.
+	InvalidValue struct {
+		// Request is the value request.
+		Request ValueRequest
+		// RequiredBy is the definition whose factory requests the value.
+		RequiredBy *Definition
+		// Err describes the problem.
+		Err error
+	}
+	// ScopeViolation describes a definition capturing a dependency with a shorter-lived scope.
+	ScopeViolation struct {
+		// Definition is the longer-lived definition requesting the dependency.
+		Definition *Definition
+		// Dependency is the shorter-lived definition being captured.
+		Dependency *Definition
+	}
+)
+
+// String returns a description of the missing dependency including the requesting factory location.
+func (m MissingDependency) String() string {
+	msg := fmt.Sprintf("definition not found: %s (required by %s at %s)",
+		m.Name, m.RequiredBy.ID(), location(m.RequiredBy))
+	for _, e := range m.Excluded {
+		msg += "; " + e.String()
+	}
+	return msg
+}
+
+// String returns the cycle as an arrow-separated path.
+func (c DependencyCycle) String() string {
+	return "cycle detected: " + strings.Join(c, " -> ")
+}
+
+// String returns a description of the ambiguous dependency and its candidate factories.
+func (a AmbiguousDependency) String() string {
+	ids := make([]string, 0, len(a.Candidates))
+	for _, def := range a.Candidates {
+		ids = append(ids, def.ID())
+	}
+	return fmt.Sprintf("ambiguous dependency: %s (required by %s at %s) has %d candidates: %s",
+		a.Name, a.RequiredBy.ID(), location(a.RequiredBy), len(a.Candidates), strings.Join(ids, ", "))
+}
+
+// String returns a description of the failed check including the factory location.
+func (f FailedCheck) String() string {
+	return fmt.Sprintf("check failed: %s (at %s): %v", f.Definition.ID(), location(f.Definition), f.Err)
+}
+
+// String returns a description of the invalid value including the requesting field and factory location.
+func (v InvalidValue) String() string {
+	return fmt.Sprintf("invalid value: %v (field %s required by %s at %s)",
+		v.Err, v.Request.Field, v.RequiredBy.ID(), location(v.RequiredBy))
+}
+
+// String returns a description of the scope violation together with the suggested fix.
+func (s ScopeViolation) String() string {
+	return fmt.Sprintf("scope violation: %s %s (at %s) captures %s %s (at %s) at construction, "+
+		"which silently turns it into a %s; inject object.Provider[%s] (or func() %s) instead and call it "+
+		"each time a fresh %s is needed, or register %s with the %s scope too",
+		s.Definition.Scope(), s.Definition.ID(), location(s.Definition),
+		s.Dependency.Scope(), s.Dependency.ID(), location(s.Dependency),
+		s.Definition.Scope(), objectType(s.Dependency), objectType(s.Dependency),
+		s.Dependency.Name(), s.Definition.Name(), s.Dependency.Scope())
+}
+
+// ValidationReport collects every problem found in a DefinitionRegistry rather than stopping at the first one.
+// Missing dependencies, cycles, invalid values and failed checks are errors; ambiguous dependencies are warnings, and scope violations
+// are errors or warnings depending on ScopePolicy. Excluded lists the definitions whose conditions
+// did not match, which are neither.
+type ValidationReport struct {
+	Missing         []MissingDependency
+	Cycles          []DependencyCycle
+	Ambiguous       []AmbiguousDependency
+	ScopeViolations []ScopeViolation
+	ScopePolicy     ScopePolicy
+	Excluded        []Exclusion
+	FailedChecks    []FailedCheck
+	InvalidValues   []InvalidValue
+}
+
+// Valid returns true if the report contains no errors.
+func (r *ValidationReport) Valid() bool {
+	return len(r.Missing) == 0 && len(r.Cycles) == 0 && len(r.FailedChecks) == 0 && len(r.InvalidValues) == 0 &&
+		(r.ScopePolicy != ScopePolicyError || len(r.ScopeViolations) == 0)
+}
+
+// Err returns all errors of the report joined together, or nil if the report is valid.
+func (r *ValidationReport) Err() error {
+	var errs []error
+	for _, m := range r.Missing {
+		errs = append(errs, errors.New(m.String()))
+	}
+	for _, c := range r.Cycles {
+		errs = append(errs, errors.New(c.String()))
+	}
+	for _, v := range r.InvalidValues {
+		errs = append(errs, errors.New(v.String()))
+	}
+	for _, f := range r.FailedChecks {
+		errs = append(errs, errors.New(f.String()))
+	}
+	if r.ScopePolicy == ScopePolicyError {
+		for _, v := range r.ScopeViolations {
+			errs = append(errs, errors.New(v.String()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Warnings returns the descriptions of all non-fatal problems of the report.
+func (r *ValidationReport) Warnings() []string {
+	var warns []string
+	for _, a := range r.Ambiguous {
+		warns = append(warns, a.String())
+	}
+	if r.ScopePolicy != ScopePolicyError {
+		for _, s := range r.ScopeViolations {
+			warns = append(warns, s.String())
+		}
+	}
+	return warns
+}
+
+// String returns a multi-line, human-readable summary of the report.
+func (r *ValidationReport) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "validation report: %d missing, %d cycles, %d invalid values, %d failed checks, %d ambiguous, "+
+		"%d scope violations, %d excluded", len(r.Missing), len(r.Cycles), len(r.InvalidValues), len(r.FailedChecks),
+		len(r.Ambiguous), len(r.ScopeViolations), len(r.Excluded))
+	if err := r.Err(); err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(sb, "\n  error: %s", line)
+		}
+	}
+	for _, warn := range r.Warnings() {
+		fmt.Fprintf(sb, "\n  warning: %s", warn)
+	}
+	for _, e := range r.Excluded {
+		fmt.Fprintf(sb, "\n  info: %s", e)
+	}
+	return sb.String()
+}
+
+// Validate checks every registered definition and returns a report of all missing dependencies,
+// dependency cycles, invalid values, failed definition checks, ambiguous dependencies and scope violations. Scope violations are only
+// collected when the registry's ScopePolicy is not ScopePolicyAllow.
+func (dr *DefaultDefRegistry) Validate() *ValidationReport {
+	report := &ValidationReport{ScopePolicy: dr.scopePolicy, Excluded: dr.Excluded()}
+	dag := util.NewDAG()
+	for _, fid := range dr.inSeq {
+		def, ok := dr.factories[fid]
+		if !ok {
+			continue
+		}
+		dag.AddNode(def.Name(), def.DependsOn()...)
+		for _, req := range def.values {
+			if _, err := dr.resolveValue(req); err != nil {
+				report.InvalidValues = append(report.InvalidValues, InvalidValue{Request: req, RequiredBy: def, Err: err})
+			}
+		}
+		for _, check := range def.checks {
+			if err := check(); err != nil {
+				report.FailedChecks = append(report.FailedChecks, FailedCheck{Definition: def, Err: err})
+			}
+		}
+		// Dependencies received through providers are looked up on every call and never widen a scope.
+		deps := def.DependsOn()
+		for i, dep := range append(deps, def.providers...) {
+			candidates := dr.GetDefinitionsByName(dep)
+			switch {
+			case len(candidates) == 0:
+				missing := MissingDependency{Name: dep, RequiredBy: def}
+				for _, e := range report.Excluded {
+					if e.Definition.Name() == dep {
+						missing.Excluded = append(missing.Excluded, e)
+					}
+				}
+				report.Missing = append(report.Missing, missing)
+				continue
+			case len(candidates) > 1:
+				report.Ambiguous = append(report.Ambiguous, AmbiguousDependency{
+					Name: dep, RequiredBy: def, Candidates: append([]*Definition{}, candidates...)})
+			}
+			if dr.scopePolicy == ScopePolicyAllow || i >= len(deps) {
+				continue
+			}
+			for _, candidate := range candidates {
+				if widensScope(def.Scope(), candidate.Scope()) {
+					report.ScopeViolations = append(report.ScopeViolations,
+						ScopeViolation{Definition: def, Dependency: candidate})
+				}
+			}
+		}
+	}
+	for _, cycle := range dag.Cycles() {
+		report.Cycles = append(report.Cycles, cycle)
+	}
+	return report
+}
+
+// objectType returns the type of the objects created by the definition's factory, or the definition name
+// if the factory type is unknown.
+func objectType(def *Definition) string {
+	if def.typ.Kind() == reflect.Func && def.typ.NumOut() == 1 {
+		return def.typ.Out(0).String()
+	}
+	return def.Name()
+}
+
+// location returns the file:line of the definition's factory function.
+func location(def *Definition) string {
+	return fmt.Sprintf("%s:%d", def.Factory().File(), def.Factory().Line())
+}
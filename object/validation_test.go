@@ -0,0 +1,186 @@
+package object
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 多个问题应一次性全部报告，而非遇到第一个即返回
+func TestValidate_CollectsAllProblems(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	a := makeTestDefinition("A", "fa", nil)
+	a.dependsOn = []string{"Missing1"}
+	b := makeTestDefinition("B", "fb", nil)
+	b.dependsOn = []string{"Missing2", "C"}
+	c := makeTestDefinition("C", "fc", nil)
+	c.dependsOn = []string{"B"}
+	x := makeTestDefinition("X", "fx", nil)
+	x.dependsOn = []string{"Y"}
+	y := makeTestDefinition("Y", "fy", nil)
+	y.dependsOn = []string{"X"}
+	for _, def := range []*Definition{a, b, c, x, y} {
+		if err := reg.register(def, false); err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+	}
+	report := reg.Validate()
+	if len(report.Missing) != 2 {
+		t.Fatalf("expected 2 missing dependencies, got %v", report.Missing)
+	}
+	if len(report.Cycles) != 2 {
+		t.Fatalf("expected 2 cycles, got %v", report.Cycles)
+	}
+	if report.Valid() {
+		t.Fatalf("report with errors should not be valid")
+	}
+	err := report.Err()
+	if err == nil || !strings.Contains(err.Error(), "Missing1") || !strings.Contains(err.Error(), "Missing2") {
+		t.Fatalf("joined error should mention all missing names, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "cycle detected") {
+		t.Fatalf("joined error should mention cycles, got %v", err)
+	}
+}
+
+func TestValidate_MissingIncludesFactoryLocation(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	def, err := reg.RegisterFactory(newValidateRoot, NewProperty(), false)
+	if err != nil {
+		t.Fatalf("RegisterFactory failed: %v", err)
+	}
+	report := reg.Validate()
+	if len(report.Missing) != 1 || report.Missing[0].RequiredBy != def {
+		t.Fatalf("unexpected missing report: %v", report.Missing)
+	}
+	if msg := report.Missing[0].String(); !strings.Contains(msg, "validation_test.go:") {
+		t.Fatalf("missing dependency should include factory file:line, got %s", msg)
+	}
+}
+
+func TestValidate_AmbiguousAndScopeAreWarnings(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	root := makeTestDefinition("Root", "froot", nil)
+	root.dependsOn = []string{"Dep"}
+	dep1 := makeTestDefinition("Dep", "fdep1", nil)
+	dep2 := makeTestDefinition("Dep", "fdep2", nil)
+	dep2.scope = Prototype
+	for _, def := range []*Definition{root, dep1, dep2} {
+		_ = reg.register(def, false)
+	}
+	report := reg.Validate()
+	if !report.Valid() || report.Err() != nil {
+		t.Fatalf("warnings only should keep report valid: %v", report)
+	}
+	if len(report.Ambiguous) != 1 || len(report.Ambiguous[0].Candidates) != 2 {
+		t.Fatalf("expected one ambiguous dependency with 2 candidates, got %v", report.Ambiguous)
+	}
+	if len(report.ScopeViolations) != 1 || report.ScopeViolations[0].Dependency != dep2 {
+		t.Fatalf("expected singleton->prototype violation, got %v", report.ScopeViolations)
+	}
+	if len(report.Warnings()) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", report.Warnings())
+	}
+}
+
+func TestValidate_InitReturnsAllErrors(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	a := makeTestDefinition("A", "fa", nil)
+	a.dependsOn = []string{"M1", "M2"}
+	_ = reg.register(a, false)
+	err := reg.Init()
+	if err == nil {
+		t.Fatalf("expected Init error")
+	}
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) || len(joined.Unwrap()) != 2 {
+		t.Fatalf("expected both missing dependencies in Init error, got %v", err)
+	}
+}
+
+func TestValidationReport_String(t *testing.T) {
+	report := &ValidationReport{Cycles: []DependencyCycle{{"A", "B", "A"}}}
+	s := report.String()
+	if !strings.Contains(s, "1 cycles") || !strings.Contains(s, "A -> B -> A") {
+		t.Fatalf("unexpected report string: %s", s)
+	}
+}
+
+type validateDep struct{}
+type validateRoot struct{}
+
+func newValidateRoot(d *validateDep) *validateRoot { return &validateRoot{} }
+
+// 构造 singleton -> prototype 的依赖
+func newScopeRegistry(policy ScopePolicy) *DefaultDefRegistry {
+	reg := NewDefinitionRegistry()
+	reg.SetScopePolicy(policy)
+	root := makeTestDefinition("Root", "froot", nil)
+	root.dependsOn = []string{"Proto"}
+	proto := makeTestDefinition("Proto", "fproto", nil)
+	proto.scope = Prototype
+	user := makeTestDefinition("User", "fuser", nil)
+	user.scope = Prototype
+	user.dependsOn = []string{"Proto"}
+	for _, def := range []*Definition{root, proto, user} {
+		_ = reg.register(def, false)
+	}
+	return reg
+}
+
+func TestValidate_ScopePolicy(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		reg := newScopeRegistry(ScopePolicyError)
+		err := reg.Init()
+		if err == nil || !strings.Contains(err.Error(), "scope violation") {
+			t.Fatalf("expected scope violation error, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "inject object.Provider[Proto] (or func() Proto) instead and call it "+
+			"each time a fresh Proto is needed, or register Root with the Prototype scope too") {
+			t.Fatalf("scope violation should suggest provider injection, got %v", err)
+		}
+	})
+	t.Run("warn", func(t *testing.T) {
+		reg := newScopeRegistry(ScopePolicyWarn)
+		report := reg.Validate()
+		// prototype -> prototype 不算违规，只有 singleton -> prototype
+		if len(report.ScopeViolations) != 1 || len(report.Warnings()) != 1 {
+			t.Fatalf("expected one scope warning, got %v", report)
+		}
+		if err := reg.Init(); err != nil {
+			t.Fatalf("warn policy should not fail Init: %v", err)
+		}
+	})
+	t.Run("allow", func(t *testing.T) {
+		reg := newScopeRegistry(ScopePolicyAllow)
+		if report := reg.Validate(); len(report.ScopeViolations) != 0 {
+			t.Fatalf("allow policy should not collect violations, got %v", report.ScopeViolations)
+		}
+	})
+}
+
+// AddChecks 添加的检查在 Validate 中执行，失败计为错误
+func TestValidate_FailedChecks(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	prop := NewProperty()
+	calls := 0
+	prop.AddChecks(func() error { calls++; return nil }, func() error { return errors.New("bad config") })
+	def, err := ParseInstance(reflect.TypeOf(&validateDep{}), &validateDep{}, prop)
+	if err != nil {
+		t.Fatalf("ParseInstance failed: %v", err)
+	}
+	if err := reg.RegisterDefinition(def, true); err != nil {
+		t.Fatalf("RegisterDefinition failed: %v", err)
+	}
+	err = reg.Init()
+	if calls != 1 {
+		t.Fatalf("every check should run once, got %d", calls)
+	}
+	if err == nil || !strings.Contains(err.Error(), "check failed") || !strings.Contains(err.Error(), "bad config") {
+		t.Fatalf("Init should fail with the check error, got %v", err)
+	}
+	if s := reg.Validate().String(); !strings.Contains(s, "1 failed checks") {
+		t.Fatalf("report should count failed checks, got %s", s)
+	}
+}
@@ -0,0 +1,217 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"vortice/util"
+)
+
+// In marks a factory parameter struct whose fields are injected one by one instead of the struct being a
+// dependency itself. Fields tagged `value:"key"` receive the named value, converted to the field type;
+// every other exported field is a dependency resolved like a factory argument.
+//
+//	type ServerParams struct {
+//		object.In
+//		Addr    string        `value:"http.addr"`
+//		Timeout time.Duration `value:"http.timeout"`
+//		Logger  *Logger
+//	}
+//
+//	func NewServer(p ServerParams) *Server
+type In struct{}
+
+var inType = reflect.TypeOf(In{})
+
+type (
+	// ValueSource provides named values, such as the entries of a config.Config.
+	ValueSource interface {
+		// Lookup returns the value of the key.
+		Lookup(key string) (any, bool)
+	}
+	// ValueRequest is a named value a definition receives through a field of an In struct.
+	ValueRequest struct {
+		// Key is the name of the value.
+		Key string
+		// Type is the type of the field the value is converted to.
+		Type reflect.Type
+		// Field names the struct field requesting the value.
+		Field string
+	}
+	// inParam describes how an argument of a factory taking In structs or providers is built from the
+	// dependencies, or from the provider of the dependency named provides.
+	inParam struct {
+		typ      reflect.Type
+		in       bool
+		fields   []inField
+		provides string
+	}
+	// inField is a field of an In struct, filled with the value request at index value, or with the next
+	// dependency if value is -1.
+	inField struct {
+		index int
+		value int
+	}
+	// valueBinding holds what is bound during Init for the factory of a definition: the values resolved for
+	// its requests, in request order, and the resolver of its providers.
+	valueBinding struct {
+		values  []reflect.Value
+		resolve ProviderResolver
+	}
+)
+
+// String returns the key and type of the request.
+func (r ValueRequest) String() string {
+	return fmt.Sprintf("%s (%s)", r.Key, r.Type)
+}
+
+// Values returns the named values the definition requests through In structs.
+func (d *Definition) Values() []ValueRequest {
+	return append([]ValueRequest{}, d.values...)
+}
+
+// isIn returns true if the type is a struct embedding In.
+func isIn(rt reflect.Type) bool {
+	if rt.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		if field := rt.Field(i); field.Anonymous && field.Type == inType {
+			return true
+		}
+	}
+	return false
+}
+
+// addIn flattens the fields of an In struct into the dependencies and value requests of the parser.
+func (p *Parser) addIn(rt reflect.Type) error {
+	param := inParam{typ: rt, in: true}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Anonymous && field.Type == inType {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("field %s of %s must be exported", field.Name, rt)
+		}
+		if key, ok := field.Tag.Lookup("value"); ok {
+			if key == "" {
+				return fmt.Errorf("field %s of %s has an empty value key", field.Name, rt)
+			}
+			param.fields = append(param.fields, inField{index: i, value: len(p.values)})
+			p.values = append(p.values, ValueRequest{Key: key, Type: field.Type, Field: rt.String() + "." + field.Name})
+			continue
+		}
+		if err := p.checkArgType(field.Type); err != nil {
+			return fmt.Errorf("field %s of %s: %w", field.Name, rt, err)
+		}
+		param.fields = append(param.fields, inField{index: i, value: -1})
+		p.addArg(field.Type)
+	}
+	p.params = append(p.params, param)
+	return nil
+}
+
+// newFactory creates the factory of the parsed function. If the function takes In structs, the factory
+// takes their dependency fields as arguments instead, and builds the structs from those and from the
+// values bound during Init. Provider arguments are left out and created from the bound resolver.
+func (p *Parser) newFactory() (*Factory, *valueBinding) {
+	factory := NewFactory(p.rv, p.argv, p.argn)
+	if !slices.ContainsFunc(p.params, func(param inParam) bool { return param.in || param.provides != "" }) {
+		return factory, nil
+	}
+	types := make([]reflect.Type, 0, len(p.argv))
+	for _, arg := range p.argv {
+		types = append(types, arg.Interface().(reflect.Type))
+	}
+	binding, params, fn := &valueBinding{}, p.params, p.rv
+	factory.fn = reflect.MakeFunc(reflect.FuncOf(types, []reflect.Type{p.obj}, false),
+		func(args []reflect.Value) []reflect.Value {
+			in := make([]reflect.Value, 0, len(params))
+			for _, param := range params {
+				if param.provides != "" {
+					in = append(in, binding.provider(param.typ, param.provides))
+					continue
+				}
+				if !param.in {
+					in, args = append(in, args[0]), args[1:]
+					continue
+				}
+				v := reflect.New(param.typ).Elem()
+				for _, field := range param.fields {
+					if field.value < 0 {
+						v.Field(field.index).Set(args[0])
+						args = args[1:]
+					} else if field.value < len(binding.values) {
+						v.Field(field.index).Set(binding.values[field.value])
+					}
+				}
+				in = append(in, v)
+			}
+			return fn.Call(in)
+		})
+	return factory, binding
+}
+
+// SetValue sets a named value requested through In structs. Values set this way are defaults: the value
+// sources take precedence. Values must be set before Init.
+func (dr *DefaultDefRegistry) SetValue(key string, value any) {
+	dr.values[key] = value
+}
+
+// AddValueSource adds a source of named values taking precedence over the sources added before and over
+// the values set with SetValue.
+func (dr *DefaultDefRegistry) AddValueSource(src ValueSource) {
+	dr.valueSources = append(dr.valueSources, src)
+}
+
+// LookupValue returns a named value from the value sources, the values set with SetValue or, for a child
+// registry, the parent registry.
+func (dr *DefaultDefRegistry) LookupValue(key string) (any, bool) {
+	for i := len(dr.valueSources) - 1; i >= 0; i-- {
+		if v, ok := dr.valueSources[i].Lookup(key); ok {
+			return v, true
+		}
+	}
+	if v, ok := dr.values[key]; ok {
+		return v, true
+	}
+	if dr.parent != nil {
+		return dr.parent.LookupValue(key)
+	}
+	return nil, false
+}
+
+// resolveValue looks up the value of the request and converts it to the requested type.
+func (dr *DefaultDefRegistry) resolveValue(req ValueRequest) (reflect.Value, error) {
+	raw, ok := dr.LookupValue(req.Key)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("value %s is not set", req.Key)
+	}
+	v, err := util.Convert(raw, req.Type)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("value %s cannot be used as %s: %w", req.Key, req.Type, err)
+	}
+	return v, nil
+}
+
+// bindValues resolves the value requests of every definition for its factory and binds the provider resolver.
+func (dr *DefaultDefRegistry) bindValues() error {
+	for _, def := range dr.GetDefinitions() {
+		if def.binding == nil {
+			continue
+		}
+		def.binding.resolve = dr.resolver
+		values := make([]reflect.Value, 0, len(def.values))
+		for _, req := range def.values {
+			v, err := dr.resolveValue(req)
+			if err != nil {
+				return err
+			}
+			values = append(values, v)
+		}
+		def.binding.values = values
+	}
+	return nil
+}
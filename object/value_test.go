@@ -0,0 +1,150 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type valueDep struct{ name string }
+type valueServer struct {
+	addr    string
+	timeout time.Duration
+	dep     *valueDep
+}
+
+type valueParams struct {
+	In
+	Addr    string        `value:"http.addr"`
+	Timeout time.Duration `value:"http.timeout"`
+	Dep     *valueDep
+}
+
+func newValueServer(p valueParams) *valueServer {
+	return &valueServer{addr: p.Addr, timeout: p.Timeout, dep: p.Dep}
+}
+
+type mapValueSource map[string]any
+
+func (m mapValueSource) Lookup(key string) (any, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// In 结构体的依赖字段被展开为工厂参数，值字段记录为值请求
+func TestParser_InStruct(t *testing.T) {
+	def, err := ParseDefinition(newValueServer, NewProperty())
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if deps := def.DependsOn(); len(deps) != 1 || deps[0] != GenerateDefinitionName(reflect.TypeOf(&valueDep{})) {
+		t.Fatalf("only the dependency field should be a dependency, got %v", deps)
+	}
+	values := def.Values()
+	if len(values) != 2 || values[0].Key != "http.addr" || values[1].Type != reflect.TypeOf(time.Duration(0)) {
+		t.Fatalf("unexpected value requests %v", values)
+	}
+	if def.Factory().Argn() != 1 || def.ID() != "vortice/object.newValueServer" {
+		t.Fatalf("factory should take the dependency and keep the original name, got %d %s",
+			def.Factory().Argn(), def.ID())
+	}
+}
+
+func TestParser_InvalidInStruct(t *testing.T) {
+	type unexported struct {
+		In
+		addr string `value:"http.addr"`
+	}
+	type emptyKey struct {
+		In
+		Addr string `value:""`
+	}
+	type badDep struct {
+		In
+		Count int
+	}
+	for _, fn := range []any{
+		func(unexported) *valueServer { return nil },
+		func(emptyKey) *valueServer { return nil },
+		func(badDep) *valueServer { return nil },
+	} {
+		if _, err := ParseDefinition(fn, NewProperty()); err == nil {
+			t.Fatalf("expected %T to be rejected", fn)
+		}
+	}
+}
+
+// 值源优先于 SetValue 设置的默认值，子注册表回退到父注册表
+func TestRegistry_LookupValue(t *testing.T) {
+	parent := NewDefinitionRegistry()
+	parent.SetValue("http.addr", ":8080")
+	parent.SetValue("http.timeout", "1s")
+	parent.AddValueSource(mapValueSource{"http.timeout": "5s"})
+	child := NewChildDefinitionRegistry(parent)
+	child.SetValue("http.addr", ":9090")
+	if v, _ := parent.LookupValue("http.timeout"); v != "5s" {
+		t.Fatalf("value sources should take precedence, got %v", v)
+	}
+	if v, _ := child.LookupValue("http.addr"); v != ":9090" {
+		t.Fatalf("child values should shadow the parent, got %v", v)
+	}
+	if v, _ := child.LookupValue("http.timeout"); v != "5s" {
+		t.Fatalf("child should fall back to the parent, got %v", v)
+	}
+	if _, ok := child.LookupValue("missing"); ok {
+		t.Fatalf("unknown key should not be found")
+	}
+}
+
+// Init 时绑定值，工厂调用时构造 In 结构体
+func TestRegistry_InitBindsValues(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	reg.SetValue("http.addr", ":8080")
+	reg.SetValue("http.timeout", "2s")
+	def, err := reg.RegisterFactory(newValueServer, NewProperty(), true)
+	if err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if _, err := reg.RegisterFactory(func() *valueDep { return &valueDep{} }, NewProperty(), true); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if err := reg.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	dep := &valueDep{name: "dep"}
+	srv := def.Factory().Call([]reflect.Value{reflect.ValueOf(dep)}).Interface().(*valueServer)
+	if srv.addr != ":8080" || srv.timeout != 2*time.Second || srv.dep != dep {
+		t.Fatalf("unexpected server %+v", srv)
+	}
+}
+
+// 缺失或类型不兼容的值在 Init 时全部报告
+func TestRegistry_InvalidValues(t *testing.T) {
+	reg := NewDefinitionRegistry()
+	reg.SetValue("http.timeout", "soon")
+	if _, err := reg.RegisterFactory(newValueServer, NewProperty(), true); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if _, err := reg.RegisterFactory(func() *valueDep { return &valueDep{} }, NewProperty(), true); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	report := reg.Validate()
+	if len(report.InvalidValues) != 2 || report.Valid() {
+		t.Fatalf("expected 2 invalid values, got %v", report.InvalidValues)
+	}
+	err := reg.Init()
+	if err == nil {
+		t.Fatalf("expected init to fail")
+	}
+	for _, want := range []string{
+		"value http.addr is not set",
+		"value http.timeout cannot be used as time.Duration",
+		"field object.valueParams.Addr",
+		"value_test.go:",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error should contain %q, got %v", want, err)
+		}
+	}
+}
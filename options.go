@@ -1,6 +1,10 @@
 package vortice
 
 import (
+	"fmt"
+	"reflect"
+	"runtime"
+
 	"vortice/object"
 )
 
@@ -8,6 +12,13 @@ import (
 // allowing modification of its attributes.
 type Option object.Option
 
+// Env is the environment passed to conditions added with WithCondition.
+type Env = object.Env
+
+// In marks a factory parameter struct whose fields are injected one by one; fields tagged `value:"key"`
+// receive named values set with Value or read from the configuration.
+type In = object.In
+
 // WithDesc sets the description of a property, providing a brief explanation
 // or additional context.
 func WithDesc(desc string) Option {
@@ -48,3 +59,54 @@ func WithAutoStartup() Option {
 		prop.AutoStartup = true
 	}
 }
+
+// WithOverride marks the registration as an intentional override: every definition already registered
+// for the same type is removed and replaced, instead of the registration failing as a duplicate.
+func WithOverride() Option {
+	return func(prop *object.Property) {
+		prop.Override = true
+	}
+}
+
+// WithProfile selects the definition only if one of the profiles is active when the container initializes.
+// A profile prefixed with "!" matches when that profile is not active.
+func WithProfile(profiles ...string) Option {
+	return func(prop *object.Property) {
+		prop.AddConditions(object.ProfileCondition(profiles...))
+	}
+}
+
+// WithCondition selects the definition only if fn returns true when the container initializes.
+func WithCondition(fn func(env Env) bool) Option {
+	return func(prop *object.Property) {
+		prop.AddConditions(object.NewCondition(funcLocation(fn), fn))
+	}
+}
+
+// WithOnMissing selects the definition only if no other definition of T is selected, so that a
+// library-provided default backs off when the application registers its own T.
+func WithOnMissing[T any]() Option {
+	name := object.GenerateDefinitionName(reflect.TypeOf((*T)(nil)).Elem())
+	return func(prop *object.Property) {
+		prop.AddConditions(object.OnMissingCondition(name))
+	}
+}
+
+// WithOnPresent selects the definition only if another definition of T is selected.
+func WithOnPresent[T any]() Option {
+	name := object.GenerateDefinitionName(reflect.TypeOf((*T)(nil)).Elem())
+	return func(prop *object.Property) {
+		prop.AddConditions(object.OnPresentCondition(name))
+	}
+}
+
+// funcLocation returns the name and file:line of a function, describing it in diagnostics.
+func funcLocation(fn any) string {
+	ptr := reflect.ValueOf(fn).Pointer()
+	f := runtime.FuncForPC(ptr)
+	if f == nil {
+		return "unknown function"
+	}
+	file, line := f.FileLine(ptr)
+	return fmt.Sprintf("%s at %s:%d", f.Name(), file, line)
+}
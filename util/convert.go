@@ -0,0 +1,192 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationType is the reflect.Type of time.Duration, parsed from strings such as "1m30s".
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Convert converts raw, a value read from a configuration source, to typ: numbers convert numerically and
+// must fit, strings are parsed as booleans, numbers or durations, and lists or comma-separated strings
+// convert element by element into slices.
+func Convert(raw any, typ reflect.Type) (reflect.Value, error) {
+	v := reflect.New(typ).Elem()
+	if err := SetValue(v, raw); err != nil {
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
+
+// SetValue converts raw to the type of v like Convert and stores it; a nil raw leaves v unchanged.
+func SetValue(v reflect.Value, raw any) error {
+	rv := reflect.ValueOf(raw)
+	if !rv.IsValid() {
+		return nil
+	}
+	if v.Type() == durationType {
+		if s, ok := raw.(string); ok {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+	}
+	if rv.Type().AssignableTo(v.Type()) {
+		v.Set(rv)
+		return nil
+	}
+	if isNumber(rv.Kind()) && isNumber(v.Kind()) {
+		return setNumber(v, rv)
+	}
+	s := fmt.Sprint(raw)
+	if rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64 {
+		// avoid the exponent fmt uses for large floats, such as JSON numbers
+		s = strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits())
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		return setSlice(v, raw)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// isNumber returns true for the integer and floating-point kinds.
+func isNumber(kind reflect.Kind) bool {
+	return reflect.Int <= kind && kind <= reflect.Float64 && kind != reflect.Uintptr
+}
+
+// setNumber converts the number rv to the numeric type of v and stores it, rejecting values that are not
+// integers when v is an integer, or that do not fit in v. Decoders such as encoding/json yield float64 for
+// every number, which must not go through its string form, e.g. 1e+06.
+func setNumber(v, rv reflect.Value) error {
+	raw := rv.Interface()
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch {
+		case rv.CanInt():
+			f = float64(rv.Int())
+		case rv.CanUint():
+			f = float64(rv.Uint())
+		default:
+			f = rv.Float()
+		}
+		if v.OverflowFloat(f) {
+			return fmt.Errorf("value %v overflows %s", raw, v.Type())
+		}
+		v.SetFloat(f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch {
+		case rv.CanInt():
+			n = rv.Int()
+		case rv.CanUint():
+			if rv.Uint() > math.MaxInt64 {
+				return fmt.Errorf("value %v overflows %s", raw, v.Type())
+			}
+			n = int64(rv.Uint())
+		default:
+			f := rv.Float()
+			if f != math.Trunc(f) {
+				return fmt.Errorf("value %v is not an integer", raw)
+			}
+			if f < math.MinInt64 || f >= math.MaxInt64 {
+				return fmt.Errorf("value %v overflows %s", raw, v.Type())
+			}
+			n = int64(f)
+		}
+		if v.OverflowInt(n) {
+			return fmt.Errorf("value %v overflows %s", raw, v.Type())
+		}
+		v.SetInt(n)
+	default:
+		var n uint64
+		switch {
+		case rv.CanInt():
+			if rv.Int() < 0 {
+				return fmt.Errorf("value %v overflows %s", raw, v.Type())
+			}
+			n = uint64(rv.Int())
+		case rv.CanUint():
+			n = rv.Uint()
+		default:
+			f := rv.Float()
+			if f != math.Trunc(f) {
+				return fmt.Errorf("value %v is not an integer", raw)
+			}
+			if f < 0 || f >= math.MaxUint64 {
+				return fmt.Errorf("value %v overflows %s", raw, v.Type())
+			}
+			n = uint64(f)
+		}
+		if v.OverflowUint(n) {
+			return fmt.Errorf("value %v overflows %s", raw, v.Type())
+		}
+		v.SetUint(n)
+	}
+	return nil
+}
+
+// setSlice stores a list, or a comma-separated string, into the slice value v.
+func setSlice(v reflect.Value, raw any) error {
+	var elems []any
+	rv := reflect.ValueOf(raw)
+	switch {
+	case rv.Kind() == reflect.String:
+		for _, s := range strings.Split(rv.String(), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				elems = append(elems, s)
+			}
+		}
+	case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elems = append(elems, rv.Index(i).Interface())
+		}
+	default:
+		return fmt.Errorf("cannot convert %T to %s", raw, v.Type())
+	}
+	out := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+	for i, elem := range elems {
+		if err := SetValue(out.Index(i), elem); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	v.Set(out)
+	return nil
+}
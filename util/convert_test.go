@@ -0,0 +1,30 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 数值按数值转换，非整数或越界的值被拒绝
+func TestConvert_Numbers(t *testing.T) {
+	cases := map[string]struct {
+		raw any
+		typ reflect.Type
+	}{
+		"is not an integer": {1.5, reflect.TypeOf(0)},
+		"overflows int8":    {float64(300), reflect.TypeOf(int8(0))},
+		"overflows uint":    {float64(-1), reflect.TypeOf(uint(0))},
+		"overflows uint16":  {70000, reflect.TypeOf(uint16(0))},
+		"overflows int64":   {1e19, reflect.TypeOf(int64(0))},
+		"overflows float32": {1e39, reflect.TypeOf(float32(0))},
+	}
+	for want, c := range cases {
+		if _, err := Convert(c.raw, c.typ); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Convert(%v, %s): expected error containing %q, got %v", c.raw, c.typ, want, err)
+		}
+	}
+	if v, err := Convert(int64(-5), reflect.TypeOf(float64(0))); err != nil || v.Float() != -5 {
+		t.Fatalf("expected -5, got %v %v", v, err)
+	}
+}
@@ -2,17 +2,23 @@ package util
 
 import (
 	"fmt"
+	"math/rand"
+	"sort"
 	"strings"
 )
 
 // DAG represents a Directed Acyclic Graph.
+// Nodes are ordered by the sequence in which they were first added, then by name for implicit
+// nodes, so sorting the same graph always yields the same result unless a random source is set.
 type DAG struct {
 	nodes map[string][]string
+	order []string
+	rnd   *rand.Rand
 }
 
 // NewDAG creates a new DAG instance.
 func NewDAG() *DAG {
-	return &DAG{nodes: make(map[string][]string)}
+	return &DAG{nodes: make(map[string][]string), order: []string{}}
 }
 
 // AddNode adds a node and its dependencies.
@@ -20,39 +26,81 @@ func NewDAG() *DAG {
 // deps: names of nodes this node depends on
 // Note: No deduplication, duplicate dependencies are allowed; no self-loop or missing dependency check.
 func (dag *DAG) AddNode(node string, deps ...string) {
+	if _, ok := dag.nodes[node]; !ok {
+		dag.order = append(dag.order, node)
+	}
 	dag.nodes[node] = append(dag.nodes[node], deps...)
 }
 
+// SetRand makes Sort break ties between independent nodes using rnd instead of insertion order.
+// It is intended for tests that want to surface hidden order dependencies; nil restores stable ordering.
+func (dag *DAG) SetRand(rnd *rand.Rand) {
+	dag.rnd = rnd
+}
+
+// Nodes returns every node of the DAG, explicit nodes in insertion order followed by
+// implicit (dependency-only) nodes sorted by name.
+func (dag *DAG) Nodes() []string {
+	nodes := append([]string{}, dag.order...)
+	var implicit []string
+	seen := map[string]bool{}
+	for _, node := range dag.order {
+		for _, dep := range dag.nodes[node] {
+			if _, ok := dag.nodes[dep]; !ok && !seen[dep] {
+				seen[dep] = true
+				implicit = append(implicit, dep)
+			}
+		}
+	}
+	sort.Strings(implicit)
+	return append(nodes, implicit...)
+}
+
 // Sort performs topological sorting on the DAG.
 // Returns: sorted node list (dependency first), or error if a cycle is detected.
+// Independent nodes keep their insertion order, see Nodes.
 func (dag *DAG) Sort() ([]string, error) {
+	ranked := dag.Nodes()
+	rank := make(map[string]int, len(ranked))
 	inDegree := map[string]int{}
-	for node := range dag.nodes {
+	for i, node := range ranked {
+		rank[node] = i
 		inDegree[node] = 0
 	}
-	for _, deps := range dag.nodes {
-		for _, dep := range deps {
+	for _, node := range dag.order {
+		for _, dep := range dag.nodes[node] {
 			inDegree[dep]++ // 允许隐式节点：未显式 AddNode 的依赖会在此加入
 		}
 	}
 
-	var queue []string
-	for k, v := range inDegree {
-		if v == 0 {
-			queue = append(queue, k)
+	// ready 按 rank 升序保存；每次取 rank 最大者，reverse 后即为插入顺序
+	var ready []string
+	push := func(node string) {
+		i := sort.Search(len(ready), func(i int) bool { return rank[ready[i]] > rank[node] })
+		ready = append(ready, "")
+		copy(ready[i+1:], ready[i:])
+		ready[i] = node
+	}
+	for _, node := range ranked {
+		if inDegree[node] == 0 {
+			push(node)
 		}
 	}
 
 	var result []string
-	for len(queue) > 0 {
-		node := queue[0]
-		queue = queue[1:]
+	for len(ready) > 0 {
+		i := len(ready) - 1
+		if dag.rnd != nil {
+			i = dag.rnd.Intn(len(ready))
+		}
+		node := ready[i]
+		ready = append(ready[:i], ready[i+1:]...)
 		result = append(result, node)
 
 		for _, dep := range dag.nodes[node] {
 			inDegree[dep]--
 			if inDegree[dep] == 0 {
-				queue = append(queue, dep)
+				push(dep)
 			}
 		}
 	}
@@ -72,6 +120,83 @@ func (dag *DAG) Sort() ([]string, error) {
 	return dag.reverse(result), nil
 }
 
+// Cycles returns one cycle path for every strongly connected component that contains a cycle,
+// each path closed by repeating its first node. Unlike Sort, which stops at the first cycle,
+// it reports all of them, in a stable order.
+func (dag *DAG) Cycles() [][]string {
+	var (
+		index   = map[string]int{}
+		low     = map[string]int{}
+		onStack = map[string]bool{}
+		stack   []string
+		counter int
+		cycles  [][]string
+	)
+	var strongConnect func(string)
+	strongConnect = func(node string) {
+		index[node], low[node] = counter, counter
+		counter++
+		stack = append(stack, node)
+		onStack[node] = true
+		for _, dep := range dag.nodes[node] {
+			if _, ok := index[dep]; !ok {
+				strongConnect(dep)
+				low[node] = min(low[node], low[dep])
+			} else if onStack[dep] {
+				low[node] = min(low[node], index[dep])
+			}
+		}
+		if low[node] != index[node] {
+			return
+		}
+		component := map[string]bool{}
+		for {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[n] = false
+			component[n] = true
+			if n == node {
+				break
+			}
+		}
+		if cycle := dag.cycleWithin(node, component); len(cycle) > 0 {
+			cycles = append(cycles, cycle)
+		}
+	}
+	for _, node := range dag.Nodes() {
+		if _, ok := index[node]; !ok {
+			strongConnect(node)
+		}
+	}
+	return cycles
+}
+
+// cycleWithin 在强连通分量内从 start 出发寻找一条回到 start 的路径
+func (dag *DAG) cycleWithin(start string, component map[string]bool) []string {
+	visited := map[string]bool{}
+	var path []string
+	var dfs func(string) bool
+	dfs = func(node string) bool {
+		path = append(path, node)
+		visited[node] = true
+		for _, dep := range dag.nodes[node] {
+			if dep == start {
+				path = append(path, start)
+				return true
+			}
+			if component[dep] && !visited[dep] && dfs(dep) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	if dfs(start) {
+		return path
+	}
+	return nil
+}
+
 // findCycle 尝试在残留节点子图中找到一条环路径
 func (dag *DAG) findCycle(inDegree map[string]int) []string {
 	// 只对 inDegree > 0 的节点进行 DFS
@@ -113,8 +238,8 @@ func (dag *DAG) findCycle(inDegree map[string]int) []string {
 		return false
 	}
 
-	for n := range subgraph {
-		if !visited[n] {
+	for _, n := range dag.Nodes() {
+		if subgraph[n] && !visited[n] {
 			if dfs(n) {
 				break
 			}
@@ -143,6 +268,7 @@ func (dag *DAG) remainingNodes(inDegree map[string]int) []string {
 			rem = append(rem, n)
 		}
 	}
+	sort.Strings(rem)
 	return rem
 }
 
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"math/rand"
 	"strings"
 	"testing"
 )
@@ -270,6 +271,82 @@ func TestDAG_ExtractCycleDirect(t *testing.T) {
 	}
 }
 
+// 独立节点按插入顺序输出，多次排序结果一致
+func TestDAG_StableInsertionOrder(t *testing.T) {
+	d := NewDAG()
+	d.AddNode("Z")
+	d.AddNode("A", "Y")
+	d.AddNode("M")
+	d.AddNode("B", "X")
+	want := "Z,Y,A,M,X,B"
+	for i := 0; i < 20; i++ {
+		order, err := d.Sort()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := strings.Join(order, ","); got != want {
+			t.Fatalf("unstable order: got %s want %s", got, want)
+		}
+	}
+}
+
+// 隐式节点按名称排在显式节点之后
+func TestDAG_NodesImplicitByName(t *testing.T) {
+	d := NewDAG()
+	d.AddNode("A", "c", "b")
+	d.AddNode("B", "a")
+	if got := strings.Join(d.Nodes(), ","); got != "A,B,a,b,c" {
+		t.Fatalf("unexpected nodes order: %s", got)
+	}
+}
+
+// 随机模式：同一种子结果可复现，且仍满足依赖优先
+func TestDAG_SetRandReproducible(t *testing.T) {
+	build := func(seed int64) []string {
+		d := NewDAG()
+		d.SetRand(rand.New(rand.NewSource(seed)))
+		for i := 0; i < 10; i++ {
+			d.AddNode(string(rune('a' + i)))
+		}
+		d.AddNode("root", "a", "j")
+		order, err := d.Sort()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return order
+	}
+	o1, o2 := build(42), build(42)
+	if strings.Join(o1, ",") != strings.Join(o2, ",") {
+		t.Fatalf("same seed should reproduce order: %v vs %v", o1, o2)
+	}
+	assertBefore(t, indexMap(o1), "a", "root")
+	assertBefore(t, indexMap(o1), "j", "root")
+}
+
+// Cycles 应报告所有强连通分量中的环，而非只报告第一个
+func TestDAG_CyclesAll(t *testing.T) {
+	d := NewDAG()
+	d.AddNode("A", "B")
+	d.AddNode("B", "A")
+	d.AddNode("C", "D")
+	d.AddNode("D", "E")
+	d.AddNode("E", "C")
+	d.AddNode("S", "S")
+	d.AddNode("F", "A")
+	cycles := d.Cycles()
+	if len(cycles) != 3 {
+		t.Fatalf("expected 3 cycles, got %v", cycles)
+	}
+	for _, c := range cycles {
+		if c[0] != c[len(c)-1] {
+			t.Fatalf("cycle should be closed: %v", c)
+		}
+	}
+	if len(NewDAG().Cycles()) != 0 {
+		t.Fatalf("empty DAG should have no cycles")
+	}
+}
+
 // 工具: 构造索引
 func indexMap(order []string) map[string]int {
 	m := map[string]int{}
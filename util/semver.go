@@ -0,0 +1,342 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version, see https://semver.org.
+type Version struct {
+	Major, Minor, Patch uint64
+	// Pre is the pre-release part without the leading '-', e.g. "rc.1".
+	Pre string
+	// Build is the build metadata without the leading '+'; it is ignored when comparing versions.
+	Build string
+}
+
+// ParseVersion parses a version of the form MAJOR.MINOR.PATCH[-PRE][+BUILD], with an optional leading 'v'.
+func ParseVersion(s string) (Version, error) {
+	v, n, err := parsePartial(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if n != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+	return v, nil
+}
+
+// parsePartial parses a version whose minor and patch may be missing or wildcards ("x", "X", "*"), as used
+// in constraints, returning the number of numeric parts given.
+func parsePartial(s string) (Version, int, error) {
+	v := Version{}
+	raw := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(raw, '+'); i >= 0 {
+		raw, v.Build = raw[:i], raw[i+1:]
+		if !validIdentifiers(v.Build, false) {
+			return Version{}, 0, fmt.Errorf("invalid version %q: bad build metadata", s)
+		}
+	}
+	if i := strings.IndexByte(raw, '-'); i >= 0 {
+		raw, v.Pre = raw[:i], raw[i+1:]
+		if !validIdentifiers(v.Pre, true) {
+			return Version{}, 0, fmt.Errorf("invalid version %q: bad pre-release", s)
+		}
+	}
+	parts := strings.Split(raw, ".")
+	if raw == "" || len(parts) > 3 {
+		return Version{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	n := 0
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		if n != i || !isNumeric(part) {
+			return Version{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		num, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return Version{}, 0, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		*nums[i] = num
+		n++
+	}
+	if n < len(parts) {
+		// everything after a wildcard must be a wildcard as well
+		for _, part := range parts[n:] {
+			if part != "x" && part != "X" && part != "*" {
+				return Version{}, 0, fmt.Errorf("invalid version %q", s)
+			}
+		}
+	}
+	if v.Pre != "" && n != 3 {
+		return Version{}, 0, fmt.Errorf("invalid version %q: pre-release requires MAJOR.MINOR.PATCH", s)
+	}
+	return v, n, nil
+}
+
+// Compare returns -1, 0 or 1 if v is lower than, equal to or greater than o, following semver precedence.
+func (v Version) Compare(o Version) int {
+	for _, pair := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	}
+	a, b := strings.Split(v.Pre, "."), strings.Split(o.Pre, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// String returns the version in its canonical form, without the leading 'v'.
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Constraint is a set of version ranges, e.g. ">=1.2.0, <2.0.0", "^1.4", "~1.2.3" or "1.x || 2.x".
+// Comparators separated by commas or spaces must all match; ranges separated by "||" are alternatives.
+type Constraint struct {
+	raw    string
+	ranges [][]comparator
+}
+
+// comparator compares a version against a bound.
+type comparator struct {
+	op    string
+	bound Version
+}
+
+// ParseConstraint parses a version constraint. An empty constraint or "*" matches every version.
+// Supported operators are =, !=, >, >=, <, <=, ^ (compatible with) and ~ (same minor version);
+// versions without an operator may be partial or contain wildcards, e.g. "1.2" or "1.2.x".
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+	for _, alt := range strings.Split(s, "||") {
+		var (
+			cmps   []comparator
+			tokens = strings.Fields(strings.ReplaceAll(alt, ",", " "))
+		)
+		for i := 0; i < len(tokens); i++ {
+			token := tokens[i]
+			// allow a space between the operator and the version, e.g. ">= 1.2"
+			if strings.TrimLeft(token, "=!<>^~") == "" && i+1 < len(tokens) {
+				i++
+				token += tokens[i]
+			}
+			expanded, err := expandComparator(token)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			cmps = append(cmps, expanded...)
+		}
+		if len(tokens) == 0 && strings.Contains(s, "||") {
+			return nil, fmt.Errorf("invalid constraint %q: empty range", s)
+		}
+		c.ranges = append(c.ranges, cmps)
+	}
+	return c, nil
+}
+
+// expandComparator turns a single comparator with a possibly partial version into primitive comparators.
+func expandComparator(token string) ([]comparator, error) {
+	op := token[:len(token)-len(strings.TrimLeft(token, "=!<>^~"))]
+	v, n, err := parsePartial(token[len(op):])
+	if err != nil {
+		return nil, err
+	}
+	// upper returns the lowest version above the range of v's first k parts, excluding its pre-releases
+	upper := func(k int) Version {
+		switch k {
+		case 0:
+			return Version{Major: v.Major + 1, Pre: "0"}
+		case 1:
+			return Version{Major: v.Major, Minor: v.Minor + 1, Pre: "0"}
+		default:
+			return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1, Pre: "0"}
+		}
+	}
+	switch op {
+	case "", "=", "==":
+		if n == 0 {
+			return nil, nil
+		}
+		if n == 3 {
+			return []comparator{{"=", v}}, nil
+		}
+		return []comparator{{">=", v}, {"<", upper(n - 1)}}, nil
+	case "!=":
+		if n != 3 {
+			return nil, fmt.Errorf("operator != requires a full version, got %q", token)
+		}
+		return []comparator{{"!=", v}}, nil
+	case ">":
+		if n == 3 {
+			return []comparator{{">", v}}, nil
+		}
+		if n == 0 {
+			return []comparator{{"<", Version{Pre: "0"}}}, nil // nothing is greater than every version
+		}
+		return []comparator{{">=", upper(n - 1)}}, nil
+	case ">=":
+		return []comparator{{">=", v}}, nil
+	case "<":
+		return []comparator{{"<", v}}, nil
+	case "<=":
+		if n == 3 {
+			return []comparator{{"<=", v}}, nil
+		}
+		if n == 0 {
+			return nil, nil
+		}
+		return []comparator{{"<", upper(n - 1)}}, nil
+	case "^":
+		// the leftmost non-zero part of the version must not change
+		switch {
+		case n == 0:
+			return nil, nil
+		case n == 1 || v.Major != 0:
+			return []comparator{{">=", v}, {"<", upper(0)}}, nil
+		case n == 2 || v.Minor != 0:
+			return []comparator{{">=", v}, {"<", upper(1)}}, nil
+		}
+		return []comparator{{">=", v}, {"<", upper(2)}}, nil
+	case "~":
+		// the minor version must not change, or the major version if no minor is given
+		switch n {
+		case 0:
+			return nil, nil
+		case 1:
+			return []comparator{{">=", v}, {"<", upper(0)}}, nil
+		}
+		return []comparator{{">=", v}, {"<", upper(1)}}, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+// Check returns true if the version satisfies the constraint.
+func (c *Constraint) Check(v Version) bool {
+	for _, cmps := range c.ranges {
+		ok := true
+		for _, cmp := range cmps {
+			if !cmp.match(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the constraint as it was parsed.
+func (c *Constraint) String() string {
+	if c.raw == "" {
+		return "*"
+	}
+	return c.raw
+}
+
+// match returns true if v satisfies the comparator.
+func (cmp comparator) match(v Version) bool {
+	r := v.Compare(cmp.bound)
+	switch cmp.op {
+	case "=":
+		return r == 0
+	case "!=":
+		return r != 0
+	case ">":
+		return r > 0
+	case ">=":
+		return r >= 0
+	case "<":
+		return r < 0
+	case "<=":
+		return r <= 0
+	}
+	return false
+}
+
+// compareIdentifier compares two pre-release identifiers: numeric identifiers compare numerically and
+// are lower than alphanumeric ones, which compare lexically.
+func compareIdentifier(a, b string) int {
+	na, nb := isNumeric(a), isNumeric(b)
+	switch {
+	case na && nb:
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case na:
+		return -1
+	case nb:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// validIdentifiers returns true if s is a dot-separated list of non-empty [0-9A-Za-z-] identifiers;
+// numeric pre-release identifiers must not have leading zeros.
+func validIdentifiers(s string, pre bool) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+		if pre && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumeric returns true if s is a non-empty string of ASCII digits.
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package util
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("v1.2.3-rc.1+build.5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Major != 1 || v.Minor != 2 || v.Patch != 3 || v.Pre != "rc.1" || v.Build != "build.5" {
+		t.Fatalf("unexpected version %+v", v)
+	}
+	if v.String() != "1.2.3-rc.1+build.5" {
+		t.Fatalf("unexpected string %s", v)
+	}
+	// 非法版本
+	for _, s := range []string{"", "1", "1.2", "1.2.x", "1.2.3.4", "a.b.c", "1.2.3-", "1.2.3-01", "1.-2.3"} {
+		if _, err := ParseVersion(s); err == nil {
+			t.Fatalf("expected error for %q", s)
+		}
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	// 按 semver 优先级升序排列
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "1.10.0", "2.0.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := ParseVersion(ordered[i])
+		b, _ := ParseVersion(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Fatalf("expected %s < %s", a, b)
+		}
+	}
+	a, _ := ParseVersion("1.0.0+a")
+	b, _ := ParseVersion("1.0.0+b")
+	if a.Compare(b) != 0 {
+		t.Fatalf("build metadata must be ignored")
+	}
+}
+
+func TestConstraint_Check(t *testing.T) {
+	cases := []struct {
+		constraint string
+		match      []string
+		reject     []string
+	}{
+		{"", []string{"0.0.1", "9.9.9"}, nil},
+		{"*", []string{"1.0.0"}, nil},
+		{"1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0", "1.3.0-rc.1", "1.1.9"}},
+		{"1.x", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{">=1.2.0, <2.0.0", []string{"1.2.0", "1.9.0"}, []string{"1.1.9", "2.0.0"}},
+		{">= 1.2 <2", []string{"1.2.0"}, []string{"2.0.0"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"!=1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0", "2.0.0-alpha"}},
+		{"^0.2.3", []string{"0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^0.2", []string{"0.2.0"}, []string{"0.3.0"}},
+		{"~1.2.3", []string{"1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1", []string{"1.9.0"}, []string{"2.0.0"}},
+		{"1.x || >=3.1", []string{"1.5.0", "3.1.0"}, []string{"2.0.0", "3.0.0"}},
+	}
+	for _, tc := range cases {
+		c, err := ParseConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.constraint, err)
+		}
+		for _, s := range tc.match {
+			if v, _ := ParseVersion(s); !c.Check(v) {
+				t.Fatalf("%q should match %s", tc.constraint, s)
+			}
+		}
+		for _, s := range tc.reject {
+			if v, _ := ParseVersion(s); c.Check(v) {
+				t.Fatalf("%q should not match %s", tc.constraint, s)
+			}
+		}
+	}
+}
+
+func TestParseConstraint_Invalid(t *testing.T) {
+	for _, s := range []string{">=", "1.2 ||", "!=1.2", "=>1.0.0", "abc", "1.x.3"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Fatalf("expected error for %q", s)
+		}
+	}
+}
@@ -3,11 +3,7 @@ package vortice
 import (
 	"context"
 	"reflect"
-	"vortice/container"
 	"vortice/object"
-	"vortice/util"
-
-	"go.uber.org/zap"
 )
 
 // GetElem retrieves an object of the specified pointer type from the container within the given context.
@@ -16,12 +12,7 @@ import (
 	var srv Service =  GetElem((*Service)(nil))
 */
 func GetElem[T any](ctx context.Context, typ *T) T {
-	coreCtx := container.WithCoreContext(ctx)
-	objs, err := container.DefaultCore().GetObjects(coreCtx, typ)
-	if err != nil || len(objs) == 0 {
-		return zeroVal(typ).(T)
-	}
-	return objs[0].Instance().(T)
+	return GetElemFrom(DefaultApp(), ctx, typ)
 }
 
 // Get retrieves an object of the specified type from the container within the given context.
@@ -30,12 +21,44 @@ func GetElem[T any](ctx context.Context, typ *T) T {
 	    var obj *Object = Get((*Object)(nil))
 */
 func Get[T any](ctx context.Context, typ T) T {
-	coreCtx := container.WithCoreContext(ctx)
-	objs, err := container.DefaultCore().GetObjects(coreCtx, typ)
-	if err != nil || len(objs) == 0 {
-		return zeroVal(typ).(T)
+	return GetFrom(DefaultApp(), ctx, typ)
+}
+
+// Value sets a named value of the default App, injected into the fields of In structs tagged with the key.
+/*
+	type ServerParams struct {
+		vortice.In
+		Addr    string        `value:"http.addr"`
+		Timeout time.Duration `value:"http.timeout"`
 	}
-	return objs[0].Instance().(T)
+	vortice.Value("http.addr", ":8080")
+	vortice.Register1(NewServer) // func NewServer(p ServerParams) *Server
+*/
+func Value(key string, value any) {
+	ValueTo(DefaultApp(), key, value)
+}
+
+// BindConfig registers *T as a component bound from the configuration section of the default App.
+/*
+	type DBConfig struct {
+		Host string `validate:"required"`
+		Port int    `default:"5432"`
+	}
+	vortice.BindConfig[DBConfig]("db")
+	vortice.Register1(NewRepository) // func NewRepository(cfg *DBConfig) *Repository
+*/
+func BindConfig[T any](section string, opts ...Option) {
+	BindConfigTo[T](DefaultApp(), section, opts...)
+}
+
+// BindReloadableConfig registers *config.Reloadable[T] and *T as components bound from the configuration
+// section of the default App, rebound whenever the configuration is reloaded.
+/*
+	vortice.BindReloadableConfig[LimitConfig]("limits")
+	vortice.Register1(NewLimiter) // func NewLimiter(r *config.Reloadable[LimitConfig]) *Limiter
+*/
+func BindReloadableConfig[T any](section string, opts ...Option) {
+	BindReloadableConfigTo[T](DefaultApp(), section, opts...)
 }
 
 // Register0 registers a factory function that takes no arguments, with optional configuration options.
@@ -77,16 +100,9 @@ func Register6[T, A, B, C, D, E, F any, FN object.FactoryFunc6[T, A, B, C, D, E,
 	register(fn, opts...)
 }
 
-// register registers a factory function with the object system, applying given options.
+// register registers a factory function with the default App, applying given options.
 func register(fn any, opts ...Option) {
-	prop := object.NewProperty()
-	for _, option := range opts {
-		option(prop)
-	}
-	prop.SetTags(container.TagAutowired)
-	if _, err := container.DefaultCore().RegisterFactory(fn, prop, true); err != nil {
-		util.Logger().Panic("register", zap.Error(err))
-	}
+	registerTo(DefaultApp(), fn, opts...)
 }
 
 // zeroVal returns a zero value for the type of the provided argument, dereferencing pointers.
@@ -1,6 +1,8 @@
 package vortice
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"vortice/object"
 )
@@ -63,3 +65,54 @@ func TestRegister0_DisallowDuplicate(t *testing.T) {
 	}()
 	Register0(f)
 }
+
+type rtOverride struct{ v int }
+
+func TestRegisterTo0_WithOverride(t *testing.T) {
+	app := NewApp(context.Background())
+	RegisterTo0(app, func() *rtOverride { return &rtOverride{v: 1} })
+	RegisterTo0(app, newRtOverride, WithOverride())
+	if err := app.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer app.Shutdown()
+	if got := GetFrom(app, context.Background(), (*rtOverride)(nil)); got == nil || got.v != 2 {
+		t.Fatalf("override should replace the earlier definition, got %+v", got)
+	}
+}
+
+func newRtOverride() *rtOverride { return &rtOverride{v: 2} }
+
+type rtStore interface{ Kind() string }
+type rtMemStore struct{}
+type rtSQLStore struct{}
+type rtFlagged struct{}
+
+func (rtMemStore) Kind() string { return "mem" }
+func (rtSQLStore) Kind() string { return "sql" }
+
+func newRtMemStore() rtStore   { return rtMemStore{} }
+func newRtSQLStore() rtStore   { return rtSQLStore{} }
+func newRtFlagged() *rtFlagged { return &rtFlagged{} }
+
+func TestRegister_ProfileAndConditions(t *testing.T) {
+	app := NewApp(context.Background())
+	app.Container().SetProfiles("prod")
+	RegisterTo0(app, newRtMemStore, WithOnMissing[rtStore]())
+	RegisterTo0(app, newRtSQLStore, WithProfile("prod"))
+	RegisterTo0(app, newRtFlagged, WithCondition(func(env Env) bool { return env.ProfileActive("beta") }))
+	if err := app.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer app.Shutdown()
+	if s := GetElemFrom(app, context.Background(), (*rtStore)(nil)); s == nil || s.Kind() != "sql" {
+		t.Fatalf("prod store should win over the default, got %v", s)
+	}
+	if GetFrom(app, context.Background(), (*rtFlagged)(nil)) != nil {
+		t.Fatalf("definition with an unmet condition should be excluded")
+	}
+	excluded := app.Container().Excluded()
+	if len(excluded) != 2 || !strings.Contains(excluded[0].Reason, "vortice_register_test.go") {
+		t.Fatalf("exclusions should name the condition location: %v", excluded)
+	}
+}
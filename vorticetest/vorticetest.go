@@ -0,0 +1,155 @@
+// Package vorticetest provides throwaway vortice Apps for tests, definition overrides and assertions
+// over the whole component graph.
+//
+//	func TestCheckout(t *testing.T) {
+//		app := vorticetest.New(t)
+//		vortice.RegisterTo1(app, NewCheckout)
+//		vortice.RegisterTo0(app, NewPaymentGateway)
+//		vorticetest.Replace[PaymentGateway](app, &fakeGateway{})
+//		vorticetest.AssertAllResolve(t, app)
+//		...
+//	}
+package vorticetest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"vortice"
+	"vortice/business"
+	"vortice/container"
+	"vortice/object"
+	"vortice/util"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// LeakTimeout is how long AssertNoGoroutineLeaks waits for goroutines to exit after Shutdown.
+	LeakTimeout = time.Second
+	// goroutineHeader matches the first line of a goroutine in a runtime.Stack dump.
+	goroutineHeader = regexp.MustCompile(`^goroutine (\d+) \[`)
+)
+
+// Option is a function type for configuring the App created by New.
+type Option func(app *vortice.App)
+
+// RandomOrder makes the App create independent components in an order shuffled with the given seed, so
+// tests relying on registration order fail; see vortice.App.SetRandomOrder.
+func RandomOrder(seed int64) Option {
+	return func(app *vortice.App) {
+		app.SetRandomOrder(seed)
+	}
+}
+
+// New creates an App independent of the default one, configured by the options, and shuts it down when the
+// test finishes.
+func New(t testing.TB, opts ...Option) *vortice.App {
+	t.Helper()
+	app := vortice.NewApp(context.Background())
+	for _, option := range opts {
+		option(app)
+	}
+	t.Cleanup(app.Shutdown)
+	return app
+}
+
+// Replace makes fake the only definition of T in the App, removing every definition registered for T so far.
+// It must be called after the definitions it overrides have been registered and before Init; later unique
+// registrations of T fail as duplicates. The fake is a singleton unless opts say otherwise.
+func Replace[T any](app *vortice.App, fake T, opts ...vortice.Option) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	prop := object.NewProperty()
+	for _, option := range opts {
+		option(prop)
+	}
+	prop.SetTags(container.TagAutowired)
+	def, err := object.ParseInstance(typ, fake, prop)
+	if err != nil {
+		util.Logger().Panic("Replace", zap.Error(err))
+	}
+	if err := app.Container().Replace(def); err != nil {
+		util.Logger().Panic("Replace", zap.Error(err))
+	}
+}
+
+// AssertAllResolve initializes the App if needed and reports every registered definition whose object
+// cannot be created, together with the location of its factory.
+func AssertAllResolve(t testing.TB, app *vortice.App) {
+	t.Helper()
+	if err := app.Init(); err != nil && !errors.Is(err, business.ErrInitialized) {
+		t.Errorf("init failed: %v", err)
+		return
+	}
+	ctx := container.WithCoreContext(context.Background())
+	for _, def := range app.Container().GetDefinitions() {
+		if _, err := app.Container().GetObjectsByName(ctx, def.Name()); err != nil {
+			t.Errorf("%s (%s:%d) does not resolve: %v",
+				def.ID(), def.Factory().File(), def.Factory().Line(), err)
+		}
+	}
+}
+
+// AssertNoGoroutineLeaks initializes the App if needed, starts and shuts it down, and reports every goroutine
+// created meanwhile that is still running LeakTimeout after Shutdown. The Cleanup registered by New then
+// finds the App already shut down.
+func AssertNoGoroutineLeaks(t testing.TB, app *vortice.App) {
+	t.Helper()
+	before := goroutines()
+	if err := app.Init(); err != nil && !errors.Is(err, business.ErrInitialized) {
+		t.Errorf("init failed: %v", err)
+		return
+	}
+	if err := app.Start(); err != nil {
+		t.Errorf("start failed: %v", err)
+	}
+	app.Shutdown()
+
+	var leaked []string
+	deadline := time.Now().Add(LeakTimeout)
+	for {
+		leaked = leaked[:0]
+		for id, stack := range goroutines() {
+			if _, ok := before[id]; !ok {
+				leaked = append(leaked, stack)
+			}
+		}
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	sort.Strings(leaked)
+	for _, stack := range leaked {
+		t.Errorf("goroutine leaked after Shutdown:\n%s", stack)
+	}
+}
+
+// goroutines returns the stacks of all goroutines except the calling one, keyed by goroutine id.
+func goroutines() map[string]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	stacks := map[string]string{}
+	for i, stack := range strings.Split(string(buf), "\n\n") {
+		m := goroutineHeader.FindStringSubmatch(stack)
+		if i == 0 || m == nil {
+			continue
+		}
+		stacks[m[1]] = stack
+	}
+	return stacks
+}
@@ -0,0 +1,174 @@
+package vorticetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"vortice"
+	"vortice/container"
+)
+
+type greeter interface{ Greet() string }
+
+type realGreeter struct{}
+
+func (realGreeter) Greet() string { return "real" }
+
+type fakeGreeter struct{}
+
+func (fakeGreeter) Greet() string { return "fake" }
+
+type welcome struct{ g greeter }
+
+func newRealGreeter() greeter         { return realGreeter{} }
+func newWelcome(g greeter) *welcome   { return &welcome{g: g} }
+func newBroken(missing *welcome) *bad { return &bad{} }
+
+type bad struct{}
+
+// ---------- 记录断言失败的 testing.TB ----------
+type recorder struct {
+	testing.TB
+	mux    sync.Mutex
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// ---------- 生命周期服务：是否清理 goroutine ----------
+type worker struct {
+	mux     sync.Mutex
+	stop    chan struct{}
+	running bool
+	leak    bool
+}
+
+func (w *worker) Start() error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.stop = make(chan struct{})
+	w.running = true
+	go func(stop chan struct{}) { <-stop }(w.stop)
+	return nil
+}
+
+func (w *worker) Stop() error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if !w.leak {
+		close(w.stop)
+	}
+	w.running = false
+	return nil
+}
+
+func (w *worker) Running() bool {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.running
+}
+
+func TestReplace_OverridesDefinition(t *testing.T) {
+	app := New(t)
+	vortice.RegisterTo0(app, newRealGreeter)
+	vortice.RegisterTo1(app, newWelcome)
+	Replace[greeter](app, fakeGreeter{})
+	AssertAllResolve(t, app)
+	w := vortice.GetFrom(app, context.Background(), (*welcome)(nil))
+	if w == nil || w.g.Greet() != "fake" {
+		t.Fatalf("dependents should receive the replacement, got %+v", w)
+	}
+}
+
+func TestReplace_WithoutOriginal(t *testing.T) {
+	app := New(t)
+	Replace[greeter](app, fakeGreeter{})
+	Replace[*welcome](app, &welcome{g: fakeGreeter{}})
+	AssertAllResolve(t, app)
+	if g := vortice.GetElemFrom(app, context.Background(), (*greeter)(nil)); g == nil || g.Greet() != "fake" {
+		t.Fatalf("replacement should be registered, got %v", g)
+	}
+}
+
+func TestAssertAllResolve_ReportsFailures(t *testing.T) {
+	app := New(t)
+	vortice.RegisterTo1(app, newBroken)
+	r := &recorder{TB: t}
+	AssertAllResolve(r, app)
+	if len(r.errors) == 0 {
+		t.Fatalf("missing dependency should be reported")
+	}
+}
+
+func TestAssertNoGoroutineLeaks(t *testing.T) {
+	LeakTimeout = 100 * time.Millisecond
+	defer func() { LeakTimeout = time.Second }()
+
+	clean := New(t)
+	vortice.RegisterTo0(clean, func() *worker { return &worker{} }, vortice.WithAutoStartup())
+	AssertNoGoroutineLeaks(t, clean)
+
+	leaky := New(t)
+	vortice.RegisterTo0(leaky, func() *worker { return &worker{leak: true} }, vortice.WithAutoStartup())
+	r := &recorder{TB: t}
+	AssertNoGoroutineLeaks(r, leaky)
+	if len(r.errors) != 1 {
+		t.Fatalf("expected one leaked goroutine, got %d: %v", len(r.errors), r.errors)
+	}
+}
+
+// ---------- 断言后的清理不应再次关闭 App ----------
+func TestAssertNoGoroutineLeaks_ShutsDownOnce(t *testing.T) {
+	shutdowns := 0
+	t.Run("app", func(t *testing.T) {
+		app := New(t)
+		container.Subscribe(app.Container().EventBus(), func(container.ContainerShutdownEvent) { shutdowns++ })
+		vortice.RegisterTo0(app, func() *worker { return &worker{} }, vortice.WithAutoStartup())
+		AssertNoGoroutineLeaks(t, app)
+	})
+	if shutdowns != 1 {
+		t.Fatalf("expected one shutdown event, got %d", shutdowns)
+	}
+}
+
+// ---------- 随机创建顺序 ----------
+type (
+	orderA struct{}
+	orderB struct{}
+	orderC struct{}
+	orderD struct{}
+)
+
+func TestRandomOrder(t *testing.T) {
+	order := func(app *vortice.App) string {
+		vortice.RegisterTo0(app, func() *orderA { return &orderA{} })
+		vortice.RegisterTo0(app, func() *orderB { return &orderB{} })
+		vortice.RegisterTo0(app, func() *orderC { return &orderC{} })
+		vortice.RegisterTo0(app, func() *orderD { return &orderD{} })
+		vortice.RegisterTo0(app, newRealGreeter)
+		vortice.RegisterTo1(app, newWelcome)
+		AssertAllResolve(t, app)
+		ids := ""
+		for _, def := range app.Container().GetDefinitions() {
+			ids += def.ID() + ";"
+		}
+		return ids
+	}
+	registered := order(New(t))
+	shuffled := false
+	for seed := int64(1); seed <= 10 && !shuffled; seed++ {
+		shuffled = order(New(t, RandomOrder(seed))) != registered
+	}
+	if !shuffled {
+		t.Fatalf("RandomOrder should change the order of independent definitions")
+	}
+}